@@ -0,0 +1,24 @@
+package gopoolx
+
+// WithAck 为任务设置确认回调，用于 SQS、NATS 等至少一次投递的消息源：任务有了最终结果时
+// 恰好调用其中一个——重试（与降级）之后最终成功时调用 ack，最终失败时以最终错误调用 nack。
+// 未能执行的任务同样以对应错误调用 nack：提交失败或被丢弃（ErrDiscarded）、出队时 ctx 已结束或排队过久、
+// 被 Cancel 取消（ErrCanceled），以及执行期间 worker 意外退出（ErrWorkerExited）。
+// 因此偏移量提交与重新投递总是与池的重试机制对齐：只有池不再重试的任务才会被确认或否认。
+//
+// 回调在 worker（或提交方）中同步调用，应避免阻塞；ack 与 nack 均可为 nil。
+func WithAck(ack func(), nack func(err error)) SubmitOption {
+	return func(o *submitOptions) {
+		o.ack, o.nack = ack, nack
+	}
+}
+
+// acknowledge 按任务的最终错误 err 调用确认回调。
+func (j *job) acknowledge(err error) {
+	switch {
+	case err == nil && j.ack != nil:
+		j.ack()
+	case err != nil && j.nack != nil:
+		j.nack(err)
+	}
+}
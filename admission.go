@@ -0,0 +1,39 @@
+package gopoolx
+
+// WithAdmissionFilter 设置准入过滤器：每个任务在登记计数、入队之前先交给 filter 检查，
+// filter 返回非 nil 错误时任务被拒绝，提交方原样得到该错误（句柄状态为 TaskFailed），任务不会执行，
+// 也不计入 Errors，只计入 Stats().Filtered。它便于把维护模式、按租户封禁、功能开关等策略集中在一处，
+// 而不必在每个调用点各自判断。
+//
+// filter 收到的 TaskInfo 包含任务名、通道、标签与提交时间，带句柄的任务还包含编号。
+// filter 在提交方的 goroutine 中同步调用，应当足够快；它发生 panic 时任务以 *HookPanicError 被拒绝。
+// SubmitAll 同样经过过滤。filter 为 nil 表示不过滤。
+func WithAdmissionFilter(filter func(info TaskInfo) error) Option {
+	return func(o *Options) {
+		o.admissionFilter = filter
+	}
+}
+
+// filterAdmission 以准入过滤器检查任务 j，未设置过滤器时直接通过。
+func (p *Pool) filterAdmission(j *job) error {
+	filter := p.opts.admissionFilter
+	if filter == nil {
+		return nil
+	}
+	info := TaskInfo{
+		Pool:        p.opts.name,
+		Name:        j.name,
+		Lane:        j.lane,
+		Tags:        j.tags,
+		Status:      TaskQueued,
+		SubmittedAt: p.opts.clock.Now(),
+	}
+	if h := j.handle; h != nil {
+		info.ID, info.SubmittedAt = h.id, h.submittedAt
+	}
+	if err := filter(info); err != nil {
+		p.stats.filtered.Add(1)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package gopoolx
+
+import "context"
+
+// attemptKey 是重试时执行次数在 ctx 中的键。
+type attemptKey struct{}
+
+// Attempt 返回 ctx 所属任务当前是第几次执行（从 1 开始），任务可以据此在重试时调整行为，
+// 例如第 2 次起切换到备用端点，或放宽自身的内部超时。
+// 首次执行不在 ctx 中附加任何值以免额外分配，因此不是由池重试的 ctx 同样返回 1。
+func Attempt(ctx context.Context) int {
+	if n, ok := ctx.Value(attemptKey{}).(int); ok {
+		return n
+	}
+	return 1
+}
+
+// attemptContext 为第 attempt 次执行准备 ctx，首次执行时原样返回。
+func attemptContext(ctx context.Context, attempt int) context.Context {
+	if attempt <= 1 {
+		return ctx
+	}
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
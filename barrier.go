@@ -0,0 +1,101 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrGangTooLarge 表示 Gang 的任务数超过了池能同时执行的任务数，任务不可能全部同时开始。
+var ErrGangTooLarge = errors.New("gang is larger than the pool's worker count")
+
+// ErrBarrierBroken 表示 Gang 中有任务未能提交或未执行就已结束（被取消、排队过久等），
+// 执行成功的任务等不到全部任务开始。
+var ErrBarrierBroken = errors.New("barrier broken: not all tasks started")
+
+// Barrier 提交一组任务，返回在它们全部结束后才完成的 Future。
+// 所有任务都成功时 Future 以 nil 完成，否则以各任务错误按传入顺序的聚合完成（errors.Join）；
+// 某个任务提交失败不会影响其余任务。tasks 为空时立即完成。
+func (p *Pool) Barrier(tasks ...Task) *Future[struct{}] {
+	return p.barrier(tasks, false)
+}
+
+// Gang 与 Barrier 相同，但额外保证：任何任务都只有在全部任务都已开始执行后才被视为成功。
+// 先执行成功的任务会继续占用 worker，等待其余任务开始，适合必须协同进行的多分片操作。
+// 有任务提交失败或未执行就结束时，等待中的任务以 ErrBarrierBroken 失败；ctx 结束时以 ctx 的错误失败。
+//
+// 任务数超过 worker 数（或同步模式下多于一个）时不会提交任何任务，直接以 ErrGangTooLarge 失败。
+// 多个 Gang 并发执行、且任务总数超过 worker 数时可能互相等待，直到 ctx 结束。
+func (p *Pool) Gang(tasks ...Task) *Future[struct{}] {
+	return p.barrier(tasks, true)
+}
+
+// barrier 是 Barrier 与 Gang 的共同实现。
+func (p *Pool) barrier(tasks []Task, gang bool) *Future[struct{}] {
+	out := newFuture[struct{}]()
+	out.pool = p
+	n := len(tasks)
+	if n == 0 {
+		out.complete(struct{}{}, nil)
+		return out
+	}
+	if gang && (n > p.workers() || p.opts.synchronous && n > 1) {
+		out.complete(struct{}{}, ErrGangTooLarge)
+		return out
+	}
+
+	var (
+		errs      = make([]error, n)
+		remaining atomic.Int64
+		// started 记录每个任务是否已开始执行（重试不重复计数），全部开始后关闭 allStarted
+		started    = make([]atomic.Bool, n)
+		numStarted atomic.Int64
+		allStarted = make(chan struct{})
+		// broken 在有任务提交失败或未执行就结束时关闭
+		broken     = make(chan struct{})
+		brokenOnce sync.Once
+	)
+	remaining.Store(int64(n))
+	finish := func(i int, err error) {
+		if gang && !started[i].Load() {
+			brokenOnce.Do(func() {
+				close(broken)
+			})
+		}
+		errs[i] = err
+		if remaining.Add(-1) == 0 {
+			out.complete(struct{}{}, errors.Join(errs...))
+		}
+	}
+
+	for i, task := range tasks {
+		if gang {
+			fn := task
+			task = func(ctx context.Context) error {
+				if started[i].CompareAndSwap(false, true) && numStarted.Add(1) == int64(n) {
+					close(allStarted)
+				}
+				if err := fn(ctx); err != nil {
+					return err
+				}
+				select {
+				case <-allStarted:
+					return nil
+				case <-broken:
+					return ErrBarrierBroken
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		j := newJob(task, nil)
+		j.after = func(err error) {
+			finish(i, err)
+		}
+		if err := p.submit(j); err != nil {
+			finish(i, err)
+		}
+	}
+	return out
+}
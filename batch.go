@@ -0,0 +1,119 @@
+package gopoolx
+
+// SubmitAll 批量提交 tasks，返回成功入队的任务数。
+// 整批任务只登记一次在途计数，减少逐个提交的同步开销。池已关闭时返回 0, ErrPoolClosed。
+// 不同队列满策略下：
+//   - QueueFullWait: 按顺序逐个入队，必要时阻塞，总是返回 len(tasks), nil
+//   - QueueFullDiscard: 放不下的任务被丢弃，accepted 为实际入队数，err 为 nil
+//   - QueueFullReturnError: 遇到第一个无法入队的任务时停止，返回已入队数与对应错误，
+//     其后的任务不会被提交；启用 WithAtomicBatch 时整批接受或整批拒绝（accepted 为 0）
+//
+// 任务被准入过滤器（WithAdmissionFilter）拒绝时，无论哪种策略都在此停止并返回过滤器的错误。
+// 内存准入（WithMemoryPressurePolicy）与 Submit 一样逐个任务按队列满策略处理。
+//
+// 与 Submit 相同，失败的提交会计入 Errors（每次调用最多记录一次）。
+func (p *Pool) SubmitAll(tasks ...Task) (accepted int, err error) {
+	if len(tasks) == 0 {
+		return 0, nil
+	}
+	jobs := make([]*job, len(tasks))
+	for i, task := range tasks {
+		jobs[i] = newJob(task, nil)
+	}
+
+	if p.opts.atomicBatch && p.queueFullPolicy() == QueueFullReturnError && !p.opts.synchronous && p.opts.queue == nil {
+		return p.submitAllAtomic(jobs)
+	}
+
+	return p.submitAllEach(jobs)
+}
+
+// submitAllEach 逐个提交 jobs，是 SubmitAll 的非原子实现。
+func (p *Pool) submitAllEach(jobs []*job) (accepted int, err error) {
+	if err := p.inflight.add(len(jobs)); err != nil {
+		return 0, err
+	}
+	for i, j := range jobs {
+		p.track(j)
+		err := p.filterAdmission(j)
+		if err == nil {
+			err = p.admitMemory()
+		}
+		if err == nil {
+			err = p.acquirePending()
+		}
+		if err == nil {
+			p.register(j)
+			// enqueue 失败时会自行释放当前任务的计数
+			err = p.enqueue(j)
+		} else {
+			p.settleRejected(j, err)
+			p.inflight.done(1)
+		}
+
+		switch err {
+		case nil:
+			accepted++
+		case ErrDiscarded:
+		default:
+			// 停止提交，撤销剩余任务预先登记的计数
+			if rest := len(jobs) - i - 1; rest > 0 {
+				p.inflight.done(rest)
+			}
+			return accepted, err
+		}
+	}
+	return accepted, nil
+}
+
+// submitAllAtomic 在写锁保护下一次性预留整批任务所需的在途名额与队列空间，
+// 空间不足时整批拒绝；预留成功后逐个经 enqueueLocked 入队，其间没有可能失败的步骤。
+// 持有写锁期间其他非阻塞提交无法入队，worker 只会腾出空间，因此预留的空间在入队前不会被占用。
+// 无缓冲队列没有可预留的空间，此时按普通的 QueueFullReturnError 语义逐个交付。
+func (p *Pool) submitAllAtomic(jobs []*job) (int, error) {
+	if cap(p.tasks) == 0 {
+		return p.submitAllEach(jobs)
+	}
+	p.batchMu.Lock()
+	defer p.batchMu.Unlock()
+
+	// 整批接受或整批拒绝：任何一个任务被准入过滤器拒绝时都不提交
+	for _, j := range jobs {
+		if err := p.filterAdmission(j); err != nil {
+			return 0, err
+		}
+	}
+	// 只使用错误模式，内存超限时直接返回 ErrMemoryPressure 而不会阻塞
+	if err := p.admitMemory(); err != nil {
+		return 0, err
+	}
+	select {
+	case <-p.quit:
+		return 0, ErrPoolClosed
+	default:
+	}
+	n := len(jobs)
+	if p.pending != nil && cap(p.pending)-len(p.pending) < n {
+		p.errs.Add(ErrMaxPending)
+		return 0, ErrMaxPending
+	}
+	if cap(p.tasks)-len(p.tasks) < n {
+		err := p.queueFullError(QueueFullReturnError)
+		p.errs.Add(err)
+		return 0, err
+	}
+
+	if err := p.inflight.add(n); err != nil {
+		return 0, err
+	}
+	for _, j := range jobs {
+		p.track(j)
+		if p.pending != nil {
+			p.pending <- struct{}{}
+		}
+		p.register(j)
+		// 空间已预留，这里的入队不会失败
+		p.enqueueLocked(j, QueueFullReturnError)
+	}
+	return n, nil
+}
@@ -0,0 +1,114 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// overLimit 是总是超过内存上限的读数。
+func overLimit() uint64 { return 1 << 40 }
+
+func TestSubmitAllMemoryPressure(t *testing.T) {
+	tasks := []Task{
+		func(context.Context) error { return nil },
+		func(context.Context) error { return nil },
+	}
+	for _, atomicBatch := range []bool{false, true} {
+		opts := []Option{
+			WithQueueFullPolicy(QueueFullReturnError),
+			WithMemoryPressurePolicy(1 << 20),
+			WithMemoryGauge(overLimit),
+		}
+		if atomicBatch {
+			opts = append(opts, WithAtomicBatch())
+		}
+		p := newRunningPool(t, 1, opts...)
+		accepted, err := p.SubmitAll(tasks...)
+		if accepted != 0 || !errors.Is(err, ErrMemoryPressure) {
+			t.Fatalf("atomic=%v: SubmitAll = %d, %v; want 0, ErrMemoryPressure", atomicBatch, accepted, err)
+		}
+		p.Wait()
+	}
+}
+
+func TestSubmitAllMemoryPressureDiscard(t *testing.T) {
+	p := newRunningPool(t, 1,
+		WithQueueFullPolicy(QueueFullDiscard),
+		WithMemoryPressurePolicy(1<<20),
+		WithMemoryGauge(overLimit),
+	)
+	ran := false
+	accepted, err := p.SubmitAll(func(context.Context) error {
+		ran = true
+		return nil
+	})
+	p.Wait()
+	if accepted != 0 || err != nil || ran {
+		t.Fatalf("SubmitAll = %d, %v (ran=%v); want 0, nil and the task discarded", accepted, err, ran)
+	}
+}
+
+func TestSubmitAllAtomicUnbuffered(t *testing.T) {
+	p := newRunningPool(t, 2, WithQueueSize(0), WithQueueFullPolicy(QueueFullReturnError), WithAtomicBatch())
+	var ran atomic.Int32
+	task := func(context.Context) error {
+		ran.Add(1)
+		return nil
+	}
+	// 没有可预留的空间时逐个交付，worker 开始接收任务后批次即可被接受
+	deadline := time.Now().Add(time.Second)
+	for {
+		n, err := p.SubmitAll(task)
+		if err == nil && n == 1 {
+			break
+		}
+		if !errors.Is(err, ErrQueueFull) {
+			t.Fatalf("SubmitAll = %d, %v; want 1, nil or ErrQueueFull", n, err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("SubmitAll on an unbuffered queue never accepted a batch")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	p.Wait()
+	if got := ran.Load(); got != 1 {
+		t.Fatalf("ran = %d, want 1", got)
+	}
+}
+
+func TestSubmitAllAtomicClosed(t *testing.T) {
+	task := func(context.Context) error { return nil }
+	opts := []Option{WithQueueSize(4), WithQueueFullPolicy(QueueFullReturnError), WithAtomicBatch()}
+
+	t.Run("waited", func(t *testing.T) {
+		p := newRunningPool(t, 1, opts...)
+		p.Wait()
+		if n, err := p.SubmitAll(task, task); n != 0 || !errors.Is(err, ErrPoolClosed) {
+			t.Fatalf("SubmitAll = %d, %v; want 0, ErrPoolClosed", n, err)
+		}
+	})
+
+	t.Run("run ended", func(t *testing.T) {
+		p := New(1, opts...)
+		ctx, cancel := context.WithCancel(context.Background())
+		go p.Run(ctx)
+		cancel()
+		<-p.quit
+		if n, err := p.SubmitAll(task, task); n != 0 || !errors.Is(err, ErrPoolClosed) {
+			t.Fatalf("SubmitAll = %d, %v; want 0, ErrPoolClosed", n, err)
+		}
+		done := make(chan struct{})
+		go func() {
+			p.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Wait blocked on a batch accepted after Run ended")
+		}
+	})
+}
@@ -0,0 +1,347 @@
+// Package bench 是比较池配置的负载场景工具：按可编程的负载（任务耗时分布、失败率、突发模式）
+// 依次驱动多个池配置，测量每个配置的吞吐、端到端延迟与每任务分配，
+// 便于在自己的硬件上对比队列后端、满队列策略与池大小等选择：
+//
+//	load := bench.Load{
+//		Tasks:       10000,
+//		Duration:    bench.Exponential(2 * time.Millisecond),
+//		FailureRate: 0.01,
+//		Burst:       bench.Burst{Size: 500, Every: 50 * time.Millisecond},
+//	}
+//	results, err := bench.Run(ctx, load,
+//		bench.Pool("16 workers", 16, gopoolx.WithQueueSize(1024)),
+//		bench.Pool("64 workers", 64, gopoolx.WithQueueSize(1024), gopoolx.WithRetry(2)),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	bench.WriteTable(os.Stdout, results)
+//
+// 任务的耗时与失败在测量开始前按 Load.Seed 确定，同一个负载在各个配置下完全相同。
+package bench
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyin49954/gopoolx"
+)
+
+// ErrInjected 是按 Load.FailureRate 注入的任务失败。
+var ErrInjected = errors.New("bench: injected failure")
+
+// Distribution 是任务耗时的分布。
+type Distribution interface {
+	// Sample 从分布中抽取一个耗时，r 由 Run 提供
+	Sample(r *rand.Rand) time.Duration
+}
+
+// DistributionFunc 将函数适配为 Distribution。
+type DistributionFunc func(r *rand.Rand) time.Duration
+
+// Sample 调用 f(r)。
+func (f DistributionFunc) Sample(r *rand.Rand) time.Duration {
+	return f(r)
+}
+
+// Fixed 返回总是取 d 的分布。
+func Fixed(d time.Duration) Distribution {
+	return DistributionFunc(func(*rand.Rand) time.Duration {
+		return d
+	})
+}
+
+// Uniform 返回在 [lo, hi] 上均匀分布的耗时。
+func Uniform(lo, hi time.Duration) Distribution {
+	return DistributionFunc(func(r *rand.Rand) time.Duration {
+		if hi <= lo {
+			return lo
+		}
+		return lo + time.Duration(r.Int64N(int64(hi-lo)+1))
+	})
+}
+
+// Exponential 返回均值为 mean 的指数分布，大多数任务很快、少数任务明显偏慢。
+func Exponential(mean time.Duration) Distribution {
+	return DistributionFunc(func(r *rand.Rand) time.Duration {
+		return time.Duration(r.ExpFloat64() * float64(mean))
+	})
+}
+
+// Bimodal 返回双峰分布：比例为 slowFraction 的任务取 slow 的样本，其余取 fast 的样本，
+// 用于模拟缓存命中与未命中、或偶发的慢查询。
+func Bimodal(fast, slow Distribution, slowFraction float64) Distribution {
+	return DistributionFunc(func(r *rand.Rand) time.Duration {
+		if r.Float64() < slowFraction {
+			return slow.Sample(r)
+		}
+		return fast.Sample(r)
+	})
+}
+
+// Burst 描述突发的提交模式：每隔 Every 一次性提交 Size 个任务。
+type Burst struct {
+	Size  int
+	Every time.Duration
+}
+
+// Load 描述一次场景的负载。
+type Load struct {
+	// Tasks 是提交的任务总数，必须大于 0
+	Tasks int
+	// Duration 是每个任务的耗时分布，为 nil 时任务立即返回
+	Duration Distribution
+	// Busy 为 true 时任务以忙循环占用 CPU 度过耗时（CPU 密集型），否则以 Sleep 度过（I/O 密集型）
+	Busy bool
+	// FailureRate 是每次执行返回 ErrInjected 的概率，每次重试独立抽取
+	FailureRate float64
+	// Rate 是每秒提交的任务数，<= 0 表示尽快提交；设置了 Burst 时不生效
+	Rate float64
+	// Burst 是突发的提交模式，Size <= 0 表示不启用
+	Burst Burst
+	// Seed 是确定任务耗时与失败的随机种子
+	Seed uint64
+}
+
+// Config 是一个被测的池配置。
+type Config struct {
+	// Name 是配置在结果中的名称
+	Name string
+	// New 为每次运行创建一个新的池；队列等带状态的选项必须在其中创建，不能在多次运行之间共用
+	New func() *gopoolx.Pool
+}
+
+// Pool 返回以 gopoolx.New(workers, opts...) 创建池的配置。
+// opts 会在每次运行时复用，因此不应包含 WithQueue 等带状态的选项，这类配置应直接构造 Config。
+func Pool(name string, workers int, opts ...gopoolx.Option) Config {
+	return Config{Name: name, New: func() *gopoolx.Pool {
+		return gopoolx.New(workers, opts...)
+	}}
+}
+
+// Latency 是端到端延迟（从提交到任务最后一次执行结束）的统计。
+type Latency struct {
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// Result 是一个配置在负载下的测量结果。
+type Result struct {
+	Name string
+	// Tasks 是提交的任务数，Dropped 是其中一次也没有执行的任务数：提交即被拒绝、被丢弃，或出队后被跳过
+	Tasks   int
+	Dropped int
+	// Succeeded、Failed 与 Retries 取自池的统计；按池的配置，提交即被拒绝的任务可能计入 Failed
+	Succeeded uint64
+	Failed    uint64
+	Retries   uint64
+	// Elapsed 是从第一次提交到全部任务结束的时长
+	Elapsed time.Duration
+	// Throughput 是每秒结束的任务数
+	Throughput float64
+	Latency    Latency
+	// AllocsPerTask 与 BytesPerTask 是运行期间每个任务平均的堆分配次数与字节数，
+	// 包含池自身与负载生成的开销（耗时的 Sleep、计时等），用于配置之间的相对比较
+	AllocsPerTask float64
+	BytesPerTask  float64
+	// Stats 是运行结束时池的统计快照
+	Stats gopoolx.Stats
+}
+
+// Run 依次以 load 驱动每个配置并返回各自的结果，顺序与 configs 相同。
+// 每个配置运行前会先触发一次 GC，避免前一个配置的垃圾影响分配统计；ctx 结束时返回已完成的结果与 ctx 错误。
+func Run(ctx context.Context, load Load, configs ...Config) ([]Result, error) {
+	if load.Tasks <= 0 {
+		return nil, errors.New("bench: Load.Tasks must be positive")
+	}
+	p := newPlan(load)
+	results := make([]Result, 0, len(configs))
+	for _, c := range configs {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		results = append(results, p.run(ctx, c))
+	}
+	return results, ctx.Err()
+}
+
+// plan 是按种子预先确定的负载，各个配置以完全相同的任务运行。
+type plan struct {
+	load      Load
+	durations []time.Duration
+	// failSeed 与任务下标、执行次数一起决定一次执行是否注入失败
+	failSeed uint64
+}
+
+func newPlan(load Load) *plan {
+	r := rand.New(rand.NewPCG(load.Seed, load.Seed^0x9e3779b97f4a7c15))
+	p := &plan{load: load, durations: make([]time.Duration, load.Tasks), failSeed: r.Uint64()}
+	if load.Duration != nil {
+		for i := range p.durations {
+			p.durations[i] = max(load.Duration.Sample(r), 0)
+		}
+	}
+	return p
+}
+
+// fails 判断第 i 个任务的第 attempt 次执行是否注入失败。
+func (p *plan) fails(i, attempt int) bool {
+	if p.load.FailureRate <= 0 {
+		return false
+	}
+	h := splitmix(p.failSeed ^ uint64(i)<<20 ^ uint64(attempt))
+	return float64(h>>11)/(1<<53) < p.load.FailureRate
+}
+
+// splitmix 是 SplitMix64 的混合函数，把相邻的输入打散为均匀的 64 位值。
+func splitmix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// run 以 c 创建池并运行一次负载。任务在测量开始前全部构造好，分配统计只包含运行期间的开销。
+func (p *plan) run(ctx context.Context, c Config) Result {
+	n := p.load.Tasks
+	submitted := make([]int64, n)
+	finished := make([]atomic.Int64, n)
+	tasks := make([]gopoolx.Task, n)
+	for i := range tasks {
+		d := p.durations[i]
+		tasks[i] = func(ctx context.Context) error {
+			spend(ctx, d, p.load.Busy)
+			finished[i].Store(time.Now().UnixNano())
+			if p.fails(i, gopoolx.Attempt(ctx)) {
+				return ErrInjected
+			}
+			return nil
+		}
+	}
+
+	pool := c.New()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go pool.Run(runCtx)
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	p.submit(ctx, pool, tasks, submitted)
+	pool.Wait()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	res := Result{
+		Name:          c.Name,
+		Tasks:         n,
+		Elapsed:       elapsed,
+		AllocsPerTask: float64(after.Mallocs-before.Mallocs) / float64(n),
+		BytesPerTask:  float64(after.TotalAlloc-before.TotalAlloc) / float64(n),
+		Stats:         pool.Stats(),
+	}
+	res.Succeeded, res.Failed, res.Retries = res.Stats.Succeeded, res.Stats.Failed, res.Stats.Retries
+	if elapsed > 0 {
+		res.Throughput = float64(res.Succeeded+res.Failed) / elapsed.Seconds()
+	}
+	for i := range finished {
+		if finished[i].Load() == 0 {
+			res.Dropped++
+		}
+	}
+	res.Latency = latency(submitted, finished)
+	return res
+}
+
+// submit 按负载的提交模式提交 tasks，记录每个被接受的任务的提交时间。ctx 结束后不再提交剩余的任务。
+func (p *plan) submit(ctx context.Context, pool *gopoolx.Pool, tasks []gopoolx.Task, submitted []int64) {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i, t := range tasks {
+		if at, ok := p.schedule(start, i); ok {
+			if wait := time.Until(at); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		submitted[i] = time.Now().UnixNano()
+		if err := pool.Submit(t); err != nil {
+			submitted[i] = 0
+		}
+	}
+}
+
+// schedule 返回第 i 个任务最早的提交时间，尽快提交时 ok 为 false。
+func (p *plan) schedule(start time.Time, i int) (at time.Time, ok bool) {
+	switch b := p.load.Burst; {
+	case b.Size > 0:
+		return start.Add(time.Duration(i/b.Size) * b.Every), true
+	case p.load.Rate > 0:
+		return start.Add(time.Duration(float64(i) / p.load.Rate * float64(time.Second))), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// spend 以 Sleep 或忙循环度过 d，ctx 结束时提前返回。
+func spend(ctx context.Context, d time.Duration, busy bool) {
+	if d <= 0 {
+		return
+	}
+	if !busy {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+		}
+		return
+	}
+	for deadline := time.Now().Add(d); time.Now().Before(deadline) && ctx.Err() == nil; {
+	}
+}
+
+// latency 由提交与结束时间计算延迟统计，只包含被接受且执行过的任务。
+func latency(submitted []int64, finished []atomic.Int64) Latency {
+	ds := make([]time.Duration, 0, len(submitted))
+	var sum time.Duration
+	for i, s := range submitted {
+		f := finished[i].Load()
+		if s == 0 || f == 0 {
+			continue
+		}
+		d := time.Duration(f - s)
+		ds = append(ds, d)
+		sum += d
+	}
+	if len(ds) == 0 {
+		return Latency{}
+	}
+	slices.Sort(ds)
+	at := func(q float64) time.Duration {
+		return ds[min(int(q*float64(len(ds))), len(ds)-1)]
+	}
+	return Latency{
+		Mean: sum / time.Duration(len(ds)),
+		P50:  at(0.5),
+		P90:  at(0.9),
+		P99:  at(0.99),
+		Max:  ds[len(ds)-1],
+	}
+}
@@ -0,0 +1,35 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteTable 将 results 写成对齐的表格，每个配置一行，便于在终端中直接比较。
+func WriteTable(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "config\ttasks\tok\tfailed\tdropped\tretries\telapsed\ttasks/s\tmean\tp50\tp90\tp99\tmax\tallocs/task\tB/task\t")
+	for _, r := range results {
+		l := r.Latency
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%v\t%.0f\t%v\t%v\t%v\t%v\t%v\t%.1f\t%.0f\t\n",
+			r.Name, r.Tasks, r.Succeeded, r.Failed, r.Dropped, r.Retries, round(r.Elapsed), r.Throughput,
+			round(l.Mean), round(l.P50), round(l.P90), round(l.P99), round(l.Max), r.AllocsPerTask, r.BytesPerTask)
+	}
+	return tw.Flush()
+}
+
+// round 按耗时的量级舍去多余的精度，使表格易读。
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	case d >= time.Microsecond:
+		return d.Round(100 * time.Nanosecond)
+	default:
+		return d
+	}
+}
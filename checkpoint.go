@@ -0,0 +1,62 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrDraining 表示池正在排空（已调用 StopAccepting 或 Kill），由 Checkpoint 返回。
+	ErrDraining = errors.New("pool is draining")
+	// ErrSoftTimeout 表示任务已超过其软超时（见 WithSoftTimeout），由 Checkpoint 返回。
+	ErrSoftTimeout = errors.New("task exceeded soft timeout")
+)
+
+// poolKey 是任务所属池在 ctx 中的键。
+type poolKey struct{}
+
+// softDeadlineKey 是任务软超时截止时间在 ctx 中的键。
+type softDeadlineKey struct{}
+
+// softDeadline 是任务的软超时截止时间及用于判断是否到期的时钟。
+type softDeadline struct {
+	at    time.Time
+	clock Clock
+}
+
+// WithSoftTimeout 为任务设置软超时 d：从任务开始执行（含全部重试）起超过 d 后，Checkpoint 返回
+// ErrSoftTimeout。与 WithHardTimeout 以及 ctx 的截止时间不同，软超时不会取消 ctx，只在任务主动调用
+// Checkpoint 时生效，任务可以借此在合适的位置保存进度后退出。d <= 0 表示不设置。
+func WithSoftTimeout(d time.Duration) SubmitOption {
+	return func(o *submitOptions) {
+		o.softTimeout = d
+	}
+}
+
+// Checkpoint 供长时间运行的任务在循环中周期性调用，以便协作式地提前结束。以下情况返回非 nil 错误，
+// 任务应尽快返回：
+//   - ctx 已结束：返回 ctx.Err()，包括 Kill、Run 的 ctx 结束、TaskHandle.Cancel，
+//     以及 ForEach 的 StopOnError 等快速失败机制取消 ctx 的情况（原因可由 context.Cause 取得）
+//   - 任务超过了软超时：返回 ErrSoftTimeout，见 WithSoftTimeout
+//   - 池正在排空：返回 ErrDraining，即已调用 StopAccepting 或 Kill；Wait 与 Shutdown 会等待任务自然结束，
+//     不视为排空
+//
+// 其余情况返回 nil。Checkpoint 不会阻塞，也不会分配内存；ctx 不是池中任务的 ctx 时只检查 ctx 本身。
+func Checkpoint(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d, ok := ctx.Value(softDeadlineKey{}).(*softDeadline); ok && !d.clock.Now().Before(d.at) {
+		return ErrSoftTimeout
+	}
+	if p, ok := ctx.Value(poolKey{}).(*Pool); ok && p.inflight.stopped.Load() {
+		return ErrDraining
+	}
+	return nil
+}
+
+// withSoftDeadline 在 ctx 中记录从 start 起 d 后到期的软超时。
+func (p *Pool) withSoftDeadline(ctx context.Context, start time.Time, d time.Duration) context.Context {
+	return context.WithValue(ctx, softDeadlineKey{}, &softDeadline{at: start.Add(d), clock: p.opts.clock})
+}
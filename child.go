@@ -0,0 +1,74 @@
+package gopoolx
+
+import "context"
+
+// Child 创建一个子池：子池拥有独立的 worker、队列、配置与 Wait/Errors，
+// 但它的每次执行都要占用父池的一个并发名额——父池及其所有子池（含更深层的子池）
+// 同时执行的任务总数不超过父池的 worker 数。适合在一个进程级并发上限之下
+// 为不同业务划分互相隔离的舱壁（bulkhead）：某个子池积压不会占满其他子池的队列，
+// 但整体并发始终受父池约束。
+//
+// 子池需要单独调用 Run 与 Wait；opts 只作用于子池，不会继承父池的配置。
+// 等待父池名额期间任务仍处于排队状态，ctx 结束时会被跳过。
+func (p *Pool) Child(workerNum int, opts ...Option) *Pool {
+	c := New(workerNum, opts...)
+	c.parent = p
+	p.enableShares()
+	return c
+}
+
+// enableShares 为池创建执行名额信号量，此后池自身及其子池的每次执行都需要占用名额。
+func (p *Pool) enableShares() {
+	p.sharesOnce.Do(func() {
+		s := make(chan struct{}, p.workers())
+		p.shares.Store(&s)
+	})
+}
+
+// shareSet 记录一次执行已占用的各级名额，以便按原样归还。
+type shareSet struct {
+	held [4]chan struct{}
+	more []chan struct{}
+	n    int
+}
+
+// acquireShares 沿父链依次占用每一级池的执行名额（以及各级池的 Governor 的名额）；ctx 结束时归还已占用的名额并返回 ctx 错误。
+func (p *Pool) acquireShares(ctx context.Context, set *shareSet) error {
+	for a := p; a != nil; a = a.parent {
+		s := a.shares.Load()
+		if s == nil {
+			continue
+		}
+		select {
+		case *s <- struct{}{}:
+			set.add(*s)
+		case <-ctx.Done():
+			set.release()
+			return ctx.Err()
+		}
+	}
+	return p.acquireGovernors(ctx, set)
+}
+
+// add 记录一个已占用的名额。
+func (set *shareSet) add(s chan struct{}) {
+	if set.n < len(set.held) {
+		set.held[set.n] = s
+	} else {
+		set.more = append(set.more, s)
+	}
+	set.n++
+}
+
+// release 归还所有已占用的名额。
+func (set *shareSet) release() {
+	for i := 0; i < set.n; i++ {
+		if i < len(set.held) {
+			<-set.held[i]
+		} else {
+			<-set.more[i-len(set.held)]
+		}
+	}
+	set.n = 0
+	set.more = nil
+}
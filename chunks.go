@@ -0,0 +1,27 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrInvalidChunkSize 表示传给 Chunks 的分块大小不是正数。
+var ErrInvalidChunkSize = errors.New("chunk size must be positive")
+
+// Chunks 将 items 按 chunkSize 切分为若干块（最后一块可能不足 chunkSize），
+// 并使用 pool 并发地对每一块执行 fn，适合批量写库等场景。
+// 每一块是 items 的子切片，与 items 共享底层数组，fn 不应越界追加元素。
+// 错误聚合与 opts（如 StopOnError）的语义与 ForEach 相同。
+func Chunks[T any](
+	pool *Pool,
+	items []T,
+	chunkSize int,
+	fn func(ctx context.Context, chunk []T) error,
+	opts ...ForEachOption,
+) error {
+	if chunkSize <= 0 {
+		return ErrInvalidChunkSize
+	}
+	return forEach(context.Background(), pool, indexed(slices.Chunk(items, chunkSize)), fn, opts)
+}
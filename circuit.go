@@ -0,0 +1,160 @@
+package gopoolx
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 表示熔断器处于打开状态，任务执行被直接短路。
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitState 表示熔断器当前所处的状态。
+type circuitState int
+
+const (
+	// circuitClosed 闭合状态：任务正常执行，统计连续失败次数与最近的失败率
+	circuitClosed circuitState = iota
+	// circuitOpen 打开状态：冷却期内所有执行都被短路
+	circuitOpen
+	// circuitHalfOpen 半开状态：冷却期结束后只放行一次试探执行
+	circuitHalfOpen
+)
+
+// circuitBreaker 是包裹任务执行的简单熔断器。
+// 连续失败次数达到 threshold，或最近 len(window) 次执行的失败率达到 ratio 后进入打开状态，
+// cooldown 时间内的执行都会返回 ErrCircuitOpen；冷却结束后放行一次试探执行，成功则闭合，失败则重新打开。
+type circuitBreaker struct {
+	mu sync.Mutex
+	// threshold 是触发熔断的连续失败次数，0 表示不按连续失败熔断
+	threshold int
+	// ratio 是触发熔断的失败率，window 是统计失败率的最近执行结果（true 为失败），
+	// 按环形缓冲区覆盖；ratio 为 0 时不按失败率熔断
+	ratio  float64
+	window []bool
+	// cooldown 是熔断打开后的冷却时间
+	cooldown time.Duration
+	// clock 是计算冷却时间使用的时钟
+	clock Clock
+
+	state circuitState
+	// failures 是闭合状态下累计的连续失败次数
+	failures int
+	// samples 是 window 中已记录的结果数，windowFailures 是其中的失败数，next 是下一个写入位置
+	samples        int
+	windowFailures int
+	next           int
+	// openedAt 记录最近一次进入打开状态的时间
+	openedAt time.Time
+	// probing 表示半开状态下是否已有试探执行在进行
+	probing bool
+}
+
+// newCircuitBreaker 按配置创建一个处于闭合状态的熔断器，未启用时返回 nil。
+func newCircuitBreaker(o *Options) *circuitBreaker {
+	cb := &circuitBreaker{cooldown: o.circuitCooldown, clock: o.clock}
+	if o.circuitThreshold > 0 {
+		cb.threshold = o.circuitThreshold
+	}
+	if o.circuitRatio > 0 && o.circuitWindow > 0 {
+		cb.ratio = o.circuitRatio
+		cb.window = make([]bool, o.circuitWindow)
+	}
+	if cb.threshold == 0 && cb.ratio == 0 {
+		return nil
+	}
+	return cb
+}
+
+// allow 判断当前是否允许执行任务，probe 表示放行的是半开状态下的试探执行。
+// ok 为 false 时调用方应直接以 ErrCircuitOpen 失败，而不是真正执行任务；
+// 执行结束后以同一个 probe 调用 record。
+func (cb *circuitBreaker) allow() (ok, probe bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if cb.clock.Now().Sub(cb.openedAt) < cb.cooldown {
+			return false, false
+		}
+		// 冷却期结束，进入半开状态并放行一次试探执行
+		cb.state = circuitHalfOpen
+		cb.probing = true
+		return true, true
+	case circuitHalfOpen:
+		if cb.probing {
+			return false, false
+		}
+		cb.probing = true
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// record 记录一次执行结果，并据此推进熔断器状态。打开状态下不计入任何结果；
+// 半开状态下只有试探执行（probe）的结果决定闭合还是重新打开，
+// 打开前就已开始、随后才结束的执行不会让熔断器提前闭合。
+func (cb *circuitBreaker) record(err error, probe bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitHalfOpen:
+		if !probe {
+			return
+		}
+		if err != nil {
+			// 试探执行失败，重新打开熔断器
+			cb.open()
+			return
+		}
+		cb.state = circuitClosed
+		cb.failures = 0
+		cb.probing = false
+	case circuitClosed:
+		if err == nil {
+			cb.failures = 0
+			cb.observe(false)
+			return
+		}
+		cb.failures++
+		tripped := cb.observe(true)
+		if tripped || cb.threshold > 0 && cb.failures >= cb.threshold {
+			cb.open()
+		}
+	}
+}
+
+// observe 将一次执行结果计入失败率窗口，返回窗口已满且失败率达到 ratio 时为 true。
+// 调用方需持有锁。
+func (cb *circuitBreaker) observe(failed bool) bool {
+	if cb.ratio == 0 {
+		return false
+	}
+	if cb.samples == len(cb.window) {
+		if cb.window[cb.next] {
+			cb.windowFailures--
+		}
+	} else {
+		cb.samples++
+	}
+	cb.window[cb.next] = failed
+	if failed {
+		cb.windowFailures++
+	}
+	cb.next = (cb.next + 1) % len(cb.window)
+	return cb.samples == len(cb.window) && float64(cb.windowFailures) >= cb.ratio*float64(len(cb.window))
+}
+
+// open 将熔断器切换到打开状态，调用方需持有锁。
+func (cb *circuitBreaker) open() {
+	cb.state = circuitOpen
+	cb.openedAt = cb.clock.Now()
+	cb.failures = 0
+	cb.probing = false
+	// 重新闭合后从空窗口开始统计，打开前的结果不再影响判断
+	clear(cb.window)
+	cb.samples, cb.windowFailures, cb.next = 0, 0, 0
+}
@@ -0,0 +1,129 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFlaky = errors.New("flaky")
+
+// TestCircuitBreakerRateInterleaved 成功与失败交替出现时连续失败次数从不超过 1，
+// 按失败率熔断的熔断器仍应在窗口记满后打开。
+func TestCircuitBreakerRateInterleaved(t *testing.T) {
+	p := New(1, WithSynchronous(), WithCircuitBreakerRate(0.5, 10, time.Hour))
+	for i := 0; i < 10; i++ {
+		fail := i%2 == 1
+		p.Submit(func(context.Context) error {
+			if fail {
+				return errFlaky
+			}
+			return nil
+		})
+	}
+	ran := false
+	p.Submit(func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if ran {
+		t.Fatal("task ran; want the breaker open after a 50% failure rate")
+	}
+	errs := p.Errors()
+	if len(errs) == 0 || !errors.Is(errs[len(errs)-1], ErrCircuitOpen) {
+		t.Fatalf("last error = %v, want ErrCircuitOpen", errs)
+	}
+}
+
+// TestCircuitBreakerRateBelowThreshold 失败率低于阈值时熔断器保持闭合。
+func TestCircuitBreakerRateBelowThreshold(t *testing.T) {
+	p := New(1, WithSynchronous(), WithCircuitBreakerRate(0.5, 10, time.Hour))
+	for i := 0; i < 30; i++ {
+		fail := i%3 == 0
+		p.Submit(func(context.Context) error {
+			if fail {
+				return errFlaky
+			}
+			return nil
+		})
+	}
+	for _, err := range p.Errors() {
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("breaker opened at a 33%% failure rate: %v", err)
+		}
+	}
+}
+
+// TestCircuitBreakerConsecutiveIgnoresInterleaved 只按连续失败熔断时，交替的成功会使其保持闭合。
+func TestCircuitBreakerConsecutiveIgnoresInterleaved(t *testing.T) {
+	p := New(1, WithSynchronous(), WithCircuitBreaker(2, time.Hour))
+	for i := 0; i < 20; i++ {
+		fail := i%2 == 0
+		p.Submit(func(context.Context) error {
+			if fail {
+				return errFlaky
+			}
+			return nil
+		})
+	}
+	for _, err := range p.Errors() {
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("consecutive breaker opened on interleaved failures: %v", err)
+		}
+	}
+}
+
+// TestCircuitBreakerIgnoresLateSuccess 熔断打开前已开始、打开后才成功的执行不应使熔断器闭合。
+func TestCircuitBreakerIgnoresLateSuccess(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opt  Option
+	}{
+		{"consecutive", WithCircuitBreaker(2, time.Hour)},
+		{"rate", WithCircuitBreakerRate(0.5, 2, time.Hour)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newRunningPool(t, 3, tc.opt)
+			started, release := make(chan struct{}), make(chan struct{})
+			p.Submit(func(context.Context) error {
+				close(started)
+				<-release
+				return nil
+			})
+			<-started
+			for i := 0; i < 2; i++ {
+				p.Submit(func(context.Context) error { return errFlaky })
+			}
+			waitFor(t, func() bool { return p.Stats().Failed == 2 })
+			close(release)
+			waitFor(t, func() bool { return p.Stats().Succeeded == 1 })
+
+			ran := false
+			p.Submit(func(context.Context) error {
+				ran = true
+				return nil
+			})
+			p.Wait()
+			if ran {
+				t.Fatal("task ran; want the breaker still open after a late success")
+			}
+			errs := p.Errors()
+			if len(errs) == 0 || !errors.Is(errs[len(errs)-1], ErrCircuitOpen) {
+				t.Fatalf("last error = %v, want ErrCircuitOpen", errs)
+			}
+		})
+	}
+}
+
+// waitFor 轮询直到 cond 成立，超过一秒仍不成立时使测试失败。
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,54 @@
+package gopoolx
+
+import "time"
+
+// Clock 是池内部获取时间与等待的来源。
+// 重试间隔、延迟提交、排队过期、熔断冷却、令牌桶限流以及任务时间戳都通过它获取时间，
+// 测试中可以用 WithClock 注入可手动推进的实现，避免依赖真实的 time.Sleep。
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// Sleep 阻塞 d 时长
+	Sleep(d time.Duration)
+	// NewTimer 创建一个在 d 之后触发的定时器
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer 是 Clock 创建的定时器，语义与 *time.Timer 相同。
+type Timer interface {
+	// C 返回定时器触发时接收时间的通道
+	C() <-chan time.Time
+	// Stop 停止定时器，返回定时器是否在触发前被停止
+	Stop() bool
+	// Reset 使定时器在 d 之后重新触发
+	Reset(d time.Duration) bool
+}
+
+// WithClock 设置池使用的时钟，nil 表示使用系统时钟（默认）。
+func WithClock(c Clock) Option {
+	return func(o *Options) {
+		if c == nil {
+			c = realClock{}
+		}
+		o.clock = c
+	}
+}
+
+// realClock 是基于 time 包的系统时钟。
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// realTimer 将 *time.Timer 适配为 Timer。
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time        { return t.t.C }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
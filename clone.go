@@ -0,0 +1,20 @@
+package gopoolx
+
+import "slices"
+
+// CloneWith 创建一个配置与 p 相同的新池：依次应用创建 p 时的全部 Option 与 opts（后者覆盖前者），
+// worker 数取 p 当前的 worker 数，适合临时启动一个与现有池配置一致的高优先级池。
+//
+// 以对象传入的配置在两个池之间共享：回调、中间件、指标 sink、追踪器、错误上报方与自定义限流器
+// 都是同一个对象，监控与日志的接线因此保持一致；内置令牌桶、水位状态等按配置为新池重新创建。
+// 自定义队列（WithQueue）与持久化目录（WithPersistentQueue）同样会被沿用，两个池不应共用它们，
+// 克隆时应以 WithQueue(nil)、WithPersistentQueue("", nil) 或新的取值覆盖。
+// 运行期间通过 SetWorkers、SetRetry、ApplyConfig 等做的调整不会被复制；p 是子池（见 Child）时，
+// 新池同样占用父池的并发名额。新池与 p 相互独立，需要单独调用 Run 与 Wait。
+func (p *Pool) CloneWith(opts ...Option) *Pool {
+	c := New(p.workers(), append(slices.Clone(p.options), opts...)...)
+	if p.parent != nil {
+		c.parent = p.parent
+	}
+	return c
+}
@@ -0,0 +1,166 @@
+package gopoolx
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithKey 为任务指定键 key，供按键处理的功能（如 WithCoalesceWindow）识别同一实体的任务。
+// 空字符串表示不指定。
+func WithKey(key string) SubmitOption {
+	return func(o *submitOptions) {
+		o.key = key
+	}
+}
+
+// WithCoalesceWindow 开启按键合并：带键（见 WithKey）的任务提交后不会立即入队，而是打开一个长度为 d 的窗口，
+// 窗口内同一个键的后续提交都合并到这一次执行中，窗口结束时只执行一次，其结果（错误，以及 SubmitWithResult
+// 的返回值）交付给窗口内的每一次提交，适合对同一实体的一连串变更通知做去抖。
+//   - 执行的是窗口内最先提交、且尚未被取消的任务，任务应在执行时读取实体的最新状态
+//   - 被合并的提交同样计入 Wait 与 WithMaxPending，在执行结束时一起结束，并计入 Stats().Coalesced
+//   - 窗口结束后（任务已入队）的提交会打开新的窗口；执行中的任务被取消时，合并到它的提交同样以 ErrCanceled 结束
+//
+// 未带键的任务、延迟任务（SubmitAfter 等）与同步模式下的任务不参与合并。d <= 0 表示不启用。
+func WithCoalesceWindow(d time.Duration) Option {
+	return func(o *Options) {
+		o.coalesceWindow = d
+	}
+}
+
+// coalescer 记录各个键当前打开的合并窗口。
+type coalescer struct {
+	window time.Duration
+	mu     sync.Mutex
+	groups map[string]*coalesceGroup
+}
+
+// newCoalescer 按配置创建合并器，未启用 WithCoalesceWindow 或处于同步模式时返回 nil。
+func newCoalescer(o *Options) *coalescer {
+	if o.coalesceWindow <= 0 || o.synchronous {
+		return nil
+	}
+	return &coalescer{window: o.coalesceWindow, groups: make(map[string]*coalesceGroup)}
+}
+
+// coalesceGroup 是一个合并窗口内同一个键的全部提交。
+type coalesceGroup struct {
+	key string
+	// members 是窗口内的提交，按提交顺序排列；窗口关闭后不再变化
+	members []*job
+	// exec 是窗口关闭时选出的实际执行的任务
+	exec atomic.Pointer[job]
+	// result 是 exec 的返回值（仅 SubmitWithResult 等带返回值的任务），由 exec 结束时写入
+	result any
+}
+
+// share 在 j 结束时交换返回值：j 是实际执行的任务时记录并返回 res，否则返回执行任务记录的值。
+func (g *coalesceGroup) share(j *job, res any) any {
+	if g.exec.Load() == j {
+		g.result = res
+		return res
+	}
+	return g.result
+}
+
+// coalesceSubmit 将已登记计数的带键任务 j 加入其键的合并窗口，没有打开的窗口时以 j 打开一个新窗口，
+// 在窗口结束时由延迟队列触发执行（见 fireCoalesced）。
+func (p *Pool) coalesceSubmit(j *job) error {
+	c := p.coalesce
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if g := c.groups[j.key]; g != nil {
+		g.members = append(g.members, j)
+		j.coalesce = g
+		p.stats.coalesced.Add(1)
+		return nil
+	}
+	g := &coalesceGroup{key: j.key, members: []*job{j}}
+	j.coalesce = g
+	if !p.pushDelayed(j, p.opts.clock.Now().Add(c.window)) {
+		j.coalesce = nil
+		p.reject(j, ErrPoolClosed)
+		return ErrPoolClosed
+	}
+	c.groups[j.key] = g
+	return nil
+}
+
+// closeWindow 关闭 g 的窗口，此后同一个键的提交会打开新的窗口。
+func (p *Pool) closeWindow(g *coalesceGroup) {
+	c := p.coalesce
+	c.mu.Lock()
+	if c.groups[g.key] == g {
+		delete(c.groups, g.key)
+	}
+	c.mu.Unlock()
+}
+
+// fireCoalesced 在 lead 打开的窗口结束时关闭窗口，并将最先提交且尚未取消的任务入队执行；
+// 全部提交都已被取消时不执行任何任务。
+func (p *Pool) fireCoalesced(lead *job) {
+	g := lead.coalesce
+	p.closeWindow(g)
+	var exec *job
+	for _, m := range g.members {
+		if m.handle == nil || m.handle.Status() == TaskQueued {
+			exec = m
+			break
+		}
+	}
+	if exec == nil {
+		return
+	}
+	g.exec.Store(exec)
+	if err := p.enqueue(exec); err != nil {
+		// 入队失败的任务已由 enqueue 释放；被取消的任务已由取消方交付结果
+		if exec.after != nil && (exec.handle == nil || exec.handle.Status() != TaskCanceled) {
+			exec.after(err)
+		}
+		p.settleCoalesced(exec, err)
+	}
+}
+
+// cancelCoalesced 在延迟队列关闭时以 ErrCanceled 结束 lead 窗口内的全部提交。
+func (p *Pool) cancelCoalesced(lead *job) {
+	g := lead.coalesce
+	p.closeWindow(g)
+	for _, m := range g.members {
+		p.settleMember(m, ErrCanceled)
+	}
+}
+
+// settleCoalesced 在实际执行的任务 j 结束（错误为 err）时，以同样的结果结束合并到它的其他提交。
+// j 不是某个窗口中实际执行的任务时不做任何事。
+func (p *Pool) settleCoalesced(j *job, err error) {
+	g := j.coalesce
+	if g.exec.Load() != j {
+		return
+	}
+	for _, m := range g.members {
+		if m != j {
+			p.settleMember(m, err)
+		}
+	}
+}
+
+// settleMember 以 err 结束一个没有实际执行的合并提交并释放其计数；已被取消的提交由取消方负责。
+func (p *Pool) settleMember(m *job, err error) {
+	if m.handle != nil {
+		status := TaskDone
+		switch {
+		case err == ErrCanceled:
+			status = TaskCanceled
+		case err != nil:
+			status = TaskFailed
+		}
+		if !m.handle.settleIfQueued(status, err) {
+			return
+		}
+	}
+	m.acknowledge(err)
+	if m.after != nil {
+		m.after(err)
+	}
+	p.done(m)
+}
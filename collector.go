@@ -0,0 +1,66 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Collector 按提交顺序收集多个 Future 的结果，
+// 调用方无需自行维护按下标写入的切片和互斥锁。
+// 典型用法：
+//
+//	c := gopoolx.NewCollector[int]()
+//	for _, id := range ids {
+//		c.Add(gopoolx.SubmitWithResult(pool, fetch(id)))
+//	}
+//	results, err := c.Wait(ctx)
+type Collector[T any] struct {
+	mu      sync.Mutex
+	futures []*Future[T]
+}
+
+// NewCollector 创建一个空的结果收集器。
+func NewCollector[T any]() *Collector[T] {
+	return &Collector[T]{}
+}
+
+// Add 登记一个 Future，返回它在结果中的下标。可并发调用。
+func (c *Collector[T]) Add(f *Future[T]) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.futures = append(c.futures, f)
+	return len(c.futures) - 1
+}
+
+// Len 返回已登记的 Future 数量。
+func (c *Collector[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.futures)
+}
+
+// Wait 等待所有已登记的 Future 完成，按登记顺序返回结果，
+// 以及按同样顺序聚合的错误（errors.Join）；失败的 Future 在结果中为零值。
+// 若 ctx 先结束，则返回 nil 与 ctx.Err()。
+func (c *Collector[T]) Wait(ctx context.Context) ([]T, error) {
+	c.mu.Lock()
+	futures := append([]*Future[T](nil), c.futures...)
+	c.mu.Unlock()
+
+	results := make([]T, len(futures))
+	var errs []error
+	for i, f := range futures {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-f.done:
+		}
+		if f.err != nil {
+			errs = append(errs, f.err)
+			continue
+		}
+		results[i] = f.result
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,165 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNoFutures 表示传给 Any / Race 的 Future 列表为空。
+var ErrNoFutures = errors.New("no futures")
+
+// All 返回一个在所有 futures 都成功后完成的 Future，结果按传入顺序排列。
+// 任意一个 Future 失败时，返回的 Future 立即以该错误完成，不再等待其余 Future。
+// futures 为空时立即以空切片完成。
+func All[T any](futures ...*Future[T]) *Future[[]T] {
+	out := newFuture[[]T]()
+	if len(futures) == 0 {
+		out.complete([]T{}, nil)
+		return out
+	}
+
+	var (
+		once      sync.Once
+		remaining atomic.Int64
+	)
+	remaining.Store(int64(len(futures)))
+	for _, f := range futures {
+		f.onComplete(func() {
+			if f.err != nil {
+				once.Do(func() {
+					out.complete(nil, f.err)
+				})
+				return
+			}
+			if remaining.Add(-1) == 0 {
+				once.Do(func() {
+					results := make([]T, len(futures))
+					for i, f := range futures {
+						results[i] = f.result
+					}
+					out.complete(results, nil)
+				})
+			}
+		})
+	}
+	return out
+}
+
+// Any 返回一个以第一个成功结果完成的 Future。
+// 只有当所有 futures 都失败时，返回的 Future 才会失败，错误为所有错误按传入顺序的聚合。
+// futures 为空时立即以 ErrNoFutures 失败。
+func Any[T any](futures ...*Future[T]) *Future[T] {
+	out := newFuture[T]()
+	if len(futures) == 0 {
+		var zero T
+		out.complete(zero, ErrNoFutures)
+		return out
+	}
+
+	var (
+		once      sync.Once
+		remaining atomic.Int64
+	)
+	remaining.Store(int64(len(futures)))
+	for _, f := range futures {
+		f.onComplete(func() {
+			if f.err == nil {
+				once.Do(func() {
+					out.complete(f.result, nil)
+				})
+				return
+			}
+			if remaining.Add(-1) == 0 {
+				once.Do(func() {
+					errs := make([]error, len(futures))
+					for i, f := range futures {
+						errs[i] = f.err
+					}
+					var zero T
+					out.complete(zero, errors.Join(errs...))
+				})
+			}
+		})
+	}
+	return out
+}
+
+// Race 返回一个以第一个完成（无论成功或失败）的 Future 的结果完成的 Future。
+// futures 为空时立即以 ErrNoFutures 失败。
+func Race[T any](futures ...*Future[T]) *Future[T] {
+	out := newFuture[T]()
+	if len(futures) == 0 {
+		var zero T
+		out.complete(zero, ErrNoFutures)
+		return out
+	}
+
+	var once sync.Once
+	for _, f := range futures {
+		f.onComplete(func() {
+			once.Do(func() {
+				out.complete(f.result, f.err)
+			})
+		})
+	}
+	return out
+}
+
+// SubmitFirstSuccess 将 fns 作为互为替代的实现（例如多个副本节点）并发提交到 pool，
+// 返回以第一个成功结果完成的 Future；一旦有实现成功，其余实现的 ctx 即被取消。
+// 只有全部实现都失败时才失败，错误为所有错误按传入顺序的聚合。
+// 因胜出者出现而被取消的实现不会重试，也不计入池的失败与 Errors。fns 为空时立即以 ErrNoFutures 失败。
+func SubmitFirstSuccess[T any](pool *Pool, fns ...func(ctx context.Context) (T, error)) *Future[T] {
+	out := newFuture[T]()
+	out.pool = pool
+	if len(fns) == 0 {
+		var zero T
+		out.complete(zero, ErrNoFutures)
+		return out
+	}
+
+	raceCtx, cancel := context.WithCancel(context.Background())
+	futures := make([]*Future[T], len(fns))
+	for i, fn := range fns {
+		futures[i] = newFuture[T]()
+		submitFunc(pool, func(ctx context.Context) (T, error) {
+			var zero T
+			if raceCtx.Err() != nil {
+				return zero, nil
+			}
+			c, stop := mergeContext(ctx, raceCtx)
+			defer stop()
+			res, err := fn(c)
+			if err != nil && raceCtx.Err() != nil {
+				// 已有其他实现胜出，本次结果会被丢弃
+				return zero, nil
+			}
+			return res, err
+		}, nil, futures[i].complete)
+	}
+
+	winner := Any(futures...)
+	winner.onComplete(func() {
+		cancel()
+		out.complete(winner.result, winner.err)
+	})
+	return out
+}
+
+// AllSettled 等待所有 futures 完成（无论成功或失败），按传入顺序返回每个 Future 的结果，
+// 从不因某个 Future 失败而提前返回，适合需要报告部分成功的批量接口。
+// 若 ctx 先结束，则返回 nil 与 ctx.Err()。
+func AllSettled[T any](ctx context.Context, futures ...*Future[T]) ([]Result[T], error) {
+	results := make([]Result[T], len(futures))
+	for i, f := range futures {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-f.done:
+		}
+		results[i] = Result[T]{Index: i, Value: f.result, Err: f.err}
+	}
+	return results, nil
+}
@@ -0,0 +1,67 @@
+package gopoolx
+
+import "time"
+
+// WithOnTaskComplete 设置任务结束回调：每个出队的任务结束时（执行完成，或因 ctx 结束、排队过久等被跳过）
+// 以其快照调用一次 fn，快照中包含任务名、排队时长、执行耗时（见 TaskInfo.ExecDuration）、执行次数与最终错误，
+// 便于在不拦截每次提交的情况下接入自定义的统计与审计。
+// 未能入队的提交（队列已满、池已关闭等）不会触发回调。
+// 回调在 worker 中同步执行，应避免阻塞；未带句柄的任务快照中 ID 为 0。
+func WithOnTaskComplete(fn func(info TaskInfo)) Option {
+	return func(o *Options) {
+		o.onTaskComplete = fn
+	}
+}
+
+// notifySkipped 为被跳过、未执行的任务触发结束回调。
+func (p *Pool) notifySkipped(j *job, status TaskStatus, err error) {
+	if j.handle != nil {
+		p.opts.onTaskComplete(j.handle.Info())
+		return
+	}
+	info := TaskInfo{
+		Pool:        p.opts.name,
+		Name:        j.name,
+		Lane:        j.lane,
+		Tags:        j.tags,
+		Status:      status,
+		SubmittedAt: j.enqueuedAt,
+		FinishedAt:  p.opts.clock.Now(),
+		Err:         err,
+	}
+	if !j.enqueuedAt.IsZero() {
+		info.QueueWait = info.FinishedAt.Sub(j.enqueuedAt)
+	}
+	p.opts.onTaskComplete(info)
+}
+
+// completed 以执行结束的任务快照 info 触发结束回调，并在 err 不为 nil 时交给错误上报方。
+func (p *Pool) completed(info TaskInfo, err error) {
+	if p.opts.onTaskComplete != nil {
+		p.opts.onTaskComplete(info)
+	}
+	if err != nil && p.opts.reporter != nil {
+		p.opts.reporter.Report(err, info)
+	}
+}
+
+// runInfo 为执行结束且没有句柄的任务生成快照，带句柄的任务直接使用句柄的快照。
+func (p *Pool) runInfo(j *job, err error, queueWait time.Duration, attempts int, start time.Time, elapsed time.Duration) TaskInfo {
+	status := TaskDone
+	if err != nil {
+		status = TaskFailed
+	}
+	return TaskInfo{
+		Pool:        p.opts.name,
+		Name:        j.name,
+		Lane:        j.lane,
+		Tags:        j.tags,
+		Status:      status,
+		SubmittedAt: j.enqueuedAt,
+		StartedAt:   start,
+		FinishedAt:  start.Add(elapsed),
+		Err:         err,
+		QueueWait:   queueWait,
+		Attempts:    attempts,
+	}
+}
@@ -0,0 +1,238 @@
+package gopoolx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// ErrInvalidConfig 表示 Config 中存在非法取值。NewFromConfig 与 Config.Validate 返回的错误包装了它，
+// 判断时应使用 errors.Is(err, ErrInvalidConfig)。
+var ErrInvalidConfig = errors.New("invalid pool config")
+
+// Config 以结构体的形式描述池的常用配置，便于从应用的配置系统接入，而不必逐个拼装 Option。
+// 零值字段表示使用默认行为（见各字段说明），由 NewFromConfig 校验并规范化。
+//
+// 字段带有 json 与 yaml 标签，可以直接从部署配置文件中读取（JSON 见 ParseConfig）。
+// JSON 中的时长既可以写成 time.ParseDuration 支持的字符串（如 "250ms"），也可以写成纳秒数；
+// QueueFullPolicy 写成 "wait"、"discard" 或 "return-error"。
+type Config struct {
+	// Workers 是 worker 数量，0 表示取 GOMAXPROCS
+	Workers int `json:"workers,omitempty" yaml:"workers,omitempty"`
+	// QueueSize 是任务队列的缓冲大小，0 表示无缓冲，见 WithQueueSize
+	QueueSize int `json:"queue_size,omitempty" yaml:"queue_size,omitempty"`
+	// QueueFullPolicy 是队列满时的处理策略，见 WithQueueFullPolicy
+	QueueFullPolicy QueueFullPolicy `json:"queue_full_policy,omitempty" yaml:"queue_full_policy,omitempty"`
+	// Retry 是失败后的最大重试次数，见 WithRetry
+	Retry int `json:"retry,omitempty" yaml:"retry,omitempty"`
+	// RetryDelay 是两次重试之间的间隔，见 WithRetryDelay
+	RetryDelay time.Duration `json:"retry_delay,omitempty" yaml:"retry_delay,omitempty"`
+	// MaxPending 是在途任务数上限，0 表示不限制，见 WithMaxPending
+	MaxPending int `json:"max_pending,omitempty" yaml:"max_pending,omitempty"`
+	// MaxQueueAge 是任务在队列中的最长等待时间，0 表示不限制，见 WithMaxQueueAge
+	MaxQueueAge time.Duration `json:"max_queue_age,omitempty" yaml:"max_queue_age,omitempty"`
+	// HardTimeout 是单次执行的硬超时，0 表示不启用，见 WithHardTimeout
+	HardTimeout time.Duration `json:"hard_timeout,omitempty" yaml:"hard_timeout,omitempty"`
+	// CircuitThreshold 与 CircuitCooldown 配置熔断器，CircuitThreshold 为 0 表示不启用，
+	// 见 WithCircuitBreaker
+	CircuitThreshold int           `json:"circuit_threshold,omitempty" yaml:"circuit_threshold,omitempty"`
+	CircuitCooldown  time.Duration `json:"circuit_cooldown,omitempty" yaml:"circuit_cooldown,omitempty"`
+	// RateLimit 与 RateBurst 配置内置令牌桶，RateLimit 为 0 表示不限流；
+	// 启用限流时 RateBurst 为 0 表示取 1，见 WithRateLimit
+	RateLimit float64 `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+	RateBurst int     `json:"rate_burst,omitempty" yaml:"rate_burst,omitempty"`
+}
+
+// Validate 检查配置中的取值是否合法，返回第一个发现的问题。
+func (c Config) Validate() error {
+	_, err := c.normalize()
+	return err
+}
+
+// normalize 校验配置，并返回将零值替换为实际默认值后的副本。
+func (c Config) normalize() (Config, error) {
+	invalid := func(field string, v any) error {
+		return fmt.Errorf("%w: %s must not be negative, got %v", ErrInvalidConfig, field, v)
+	}
+	switch {
+	case c.Workers < 0:
+		return c, invalid("Workers", c.Workers)
+	case c.QueueSize < 0:
+		return c, invalid("QueueSize", c.QueueSize)
+	case c.Retry < 0:
+		return c, invalid("Retry", c.Retry)
+	case c.RetryDelay < 0:
+		return c, invalid("RetryDelay", c.RetryDelay)
+	case c.MaxPending < 0:
+		return c, invalid("MaxPending", c.MaxPending)
+	case c.MaxQueueAge < 0:
+		return c, invalid("MaxQueueAge", c.MaxQueueAge)
+	case c.HardTimeout < 0:
+		return c, invalid("HardTimeout", c.HardTimeout)
+	case c.CircuitThreshold < 0:
+		return c, invalid("CircuitThreshold", c.CircuitThreshold)
+	case c.CircuitCooldown < 0:
+		return c, invalid("CircuitCooldown", c.CircuitCooldown)
+	case c.RateLimit < 0:
+		return c, invalid("RateLimit", c.RateLimit)
+	case c.RateBurst < 0:
+		return c, invalid("RateBurst", c.RateBurst)
+	}
+	if c.QueueFullPolicy < QueueFullWait || c.QueueFullPolicy > QueueFullReturnError {
+		return c, fmt.Errorf("%w: unknown QueueFullPolicy %d", ErrInvalidConfig, c.QueueFullPolicy)
+	}
+	if c.CircuitThreshold > 0 && c.CircuitCooldown == 0 {
+		return c, fmt.Errorf("%w: CircuitCooldown is required when CircuitThreshold is set", ErrInvalidConfig)
+	}
+
+	if c.Workers == 0 {
+		c.Workers = runtime.GOMAXPROCS(0)
+	}
+	if c.RateLimit > 0 && c.RateBurst == 0 {
+		c.RateBurst = 1
+	}
+	return c, nil
+}
+
+// options 将已规范化的配置转换为对应的 Option。
+func (c Config) options() []Option {
+	opts := []Option{
+		WithQueueSize(c.QueueSize),
+		WithQueueFullPolicy(c.QueueFullPolicy),
+		WithRetry(c.Retry),
+		WithRetryDelay(c.RetryDelay),
+		WithMaxPending(c.MaxPending),
+	}
+	if c.MaxQueueAge > 0 {
+		opts = append(opts, WithMaxQueueAge(c.MaxQueueAge, nil))
+	}
+	if c.HardTimeout > 0 {
+		opts = append(opts, WithHardTimeout(c.HardTimeout, nil))
+	}
+	if c.CircuitThreshold > 0 {
+		opts = append(opts, WithCircuitBreaker(c.CircuitThreshold, c.CircuitCooldown))
+	}
+	if c.RateLimit > 0 {
+		opts = append(opts, WithRateLimit(c.RateLimit, c.RateBurst))
+	}
+	return opts
+}
+
+// NewFromConfig 按 cfg 创建池，cfg 中的非法取值（负数、未知的策略等）以包装了 ErrInvalidConfig 的错误返回。
+// opts 在配置之后应用，用于设置 Config 无法表达的选项（回调、自定义队列等），也可以覆盖配置中的同名项。
+// 与 New 相同，返回的池需要调用 Run 启动。
+func NewFromConfig(cfg Config, opts ...Option) (*Pool, error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg.Workers, append(cfg.options(), opts...)...), nil
+}
+
+// ParseConfig 从 JSON 数据中解析并校验配置。未知的字段同样视为错误，以便尽早发现拼写错误；
+// 格式错误与非法取值返回的错误都包装了 ErrInvalidConfig。YAML 配置可以用任意 YAML 库
+// 按字段的 yaml 标签解码后，再调用 Validate 校验。
+func ParseConfig(data []byte) (Config, error) {
+	var cfg Config
+	// 直接解码到 configJSON，使 DisallowUnknownFields 对 Config 的字段生效
+	aux := configJSON{configAlias: (*configAlias)(&cfg)}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&aux); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	aux.store(&cfg)
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// configDuration 是 Config 在 JSON 中使用的时长，编码为 "1.5s" 形式的字符串，
+// 解码时同时接受字符串与纳秒数。
+type configDuration time.Duration
+
+// MarshalJSON 将时长编码为 time.Duration.String 的形式。
+func (d configDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON 解析字符串形式的时长或纳秒数。
+func (d *configDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var ns int64
+		if err := json.Unmarshal(data, &ns); err != nil {
+			return fmt.Errorf("invalid duration %s", data)
+		}
+		*d = configDuration(ns)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = configDuration(v)
+	return nil
+}
+
+// configAlias 是去掉 JSON 方法的 Config，避免 MarshalJSON / UnmarshalJSON 递归调用自身。
+type configAlias Config
+
+// configJSON 是 Config 的 JSON 形式：时长字段以 configDuration 覆盖原字段。
+type configJSON struct {
+	*configAlias
+	RetryDelay      configDuration `json:"retry_delay,omitempty"`
+	MaxQueueAge     configDuration `json:"max_queue_age,omitempty"`
+	HardTimeout     configDuration `json:"hard_timeout,omitempty"`
+	CircuitCooldown configDuration `json:"circuit_cooldown,omitempty"`
+}
+
+// MarshalJSON 将配置编码为 JSON，时长字段编码为字符串。
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(configJSON{
+		configAlias:     (*configAlias)(&c),
+		RetryDelay:      configDuration(c.RetryDelay),
+		MaxQueueAge:     configDuration(c.MaxQueueAge),
+		HardTimeout:     configDuration(c.HardTimeout),
+		CircuitCooldown: configDuration(c.CircuitCooldown),
+	})
+}
+
+// UnmarshalJSON 从 JSON 解码配置，时长字段接受字符串或纳秒数。
+func (c *Config) UnmarshalJSON(data []byte) error {
+	aux := configJSON{configAlias: (*configAlias)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	aux.store(c)
+	return nil
+}
+
+// store 将解码得到的时长字段写回 c。
+func (aux *configJSON) store(c *Config) {
+	c.RetryDelay = time.Duration(aux.RetryDelay)
+	c.MaxQueueAge = time.Duration(aux.MaxQueueAge)
+	c.HardTimeout = time.Duration(aux.HardTimeout)
+	c.CircuitCooldown = time.Duration(aux.CircuitCooldown)
+}
+
+// ApplyConfig 在运行期间将 cfg 应用到池：调整 worker 数（见 SetWorkers）、重试次数与间隔、
+// 队列满策略与限流（见 SetRateLimit），便于在不重新部署的情况下调整行为异常的池。
+// QueueSize、MaxPending、MaxQueueAge、HardTimeout 与熔断器只在创建池时生效，这里会被忽略。
+// cfg 非法时返回包装了 ErrInvalidConfig 的错误，此时不修改任何配置。
+func (p *Pool) ApplyConfig(cfg Config) error {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return err
+	}
+	p.SetWorkers(cfg.Workers)
+	p.SetRetry(cfg.Retry)
+	p.SetRetryDelay(cfg.RetryDelay)
+	p.SetQueueFullPolicy(cfg.QueueFullPolicy)
+	p.SetRateLimit(cfg.RateLimit, cfg.RateBurst)
+	return nil
+}
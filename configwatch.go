@@ -0,0 +1,55 @@
+package gopoolx
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchConfig 每隔 interval 调用一次 load 读取配置，配置与上次应用的不同时以 ApplyConfig 应用到池。
+// load 或 ApplyConfig 返回的错误以 onErr（可为 nil）报告，池保持原有配置，下一轮继续读取。
+// WatchConfig 会阻塞，直到 ctx 结束（返回 ctx 的错误）或池被关闭（返回 nil），通常在独立的 goroutine 中调用：
+//
+//	go pool.WatchConfig(ctx, 10*time.Second, gopoolx.ConfigFile("/etc/app/pool.json"), func(err error) {
+//		log.Printf("pool config: %v", err)
+//	})
+func (p *Pool) WatchConfig(ctx context.Context, interval time.Duration, load func() (Config, error), onErr func(error)) error {
+	timer := p.opts.clock.NewTimer(interval)
+	defer timer.Stop()
+
+	var (
+		last    Config
+		applied bool
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.closed:
+			return nil
+		case <-timer.C():
+		}
+		timer.Reset(interval)
+
+		cfg, err := load()
+		if err == nil && (!applied || cfg != last) {
+			if err = p.ApplyConfig(cfg); err == nil {
+				last, applied = cfg, true
+			}
+		}
+		if err != nil && onErr != nil {
+			onErr(err)
+		}
+	}
+}
+
+// ConfigFile 返回从 path 读取 JSON 配置（见 ParseConfig）的加载函数，可直接传给 WatchConfig。
+func ConfigFile(path string) func() (Config, error) {
+	return func() (Config, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		return ParseConfig(data)
+	}
+}
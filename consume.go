@@ -0,0 +1,43 @@
+package gopoolx
+
+import "context"
+
+// Consume 持续从 src 中读取任务并提交到池中，使池可以直接消费已有的生产者通道
+// （例如由 Kafka 消费者填充的通道），任务同样享有重试、panic 恢复与错误收集。
+// opts 作用于每一个读取到的任务。
+//
+// Consume 会阻塞：src 被关闭时返回 nil，ctx 结束时返回 ctx 错误，池已关闭时返回 ErrPoolClosed。
+// 其他提交失败（例如返回错误模式下队列已满）按 Submit 的语义处理并继续消费。
+// 读取与提交在调用方 goroutine 中串行进行，队列满时的等待会自然地对 src 形成背压。
+func (p *Pool) Consume(ctx context.Context, src <-chan Task, opts ...SubmitOption) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case task, ok := <-src:
+			if !ok {
+				return nil
+			}
+			if err := p.Submit(task, opts...); err == ErrPoolClosed {
+				return err
+			}
+		}
+	}
+}
+
+// Consume 持续从 src 中读取输入值并交给 handler 处理，语义与 Pool.Consume 相同。
+func (tp *TypedPool[In]) Consume(ctx context.Context, src <-chan In, opts ...SubmitOption) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case in, ok := <-src:
+			if !ok {
+				return nil
+			}
+			if err := tp.Submit(in, opts...); err == ErrPoolClosed {
+				return err
+			}
+		}
+	}
+}
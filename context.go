@@ -0,0 +1,30 @@
+package gopoolx
+
+import "context"
+
+// SubmitWithContext 提交一个绑定了提交方上下文 taskCtx 的任务。
+// 任务执行时的 ctx 同时派生自 taskCtx 与 Run 的 ctx：任一结束都会使其取消，
+// 上下文中的值（如 trace ID）取自 taskCtx。适合请求级任务：请求被取消后，
+// 正在执行的任务能及时感知；出队时 taskCtx 已结束的任务不会执行，而是直接以 ctx 错误结束。
+// 其余语义与 Submit 相同。
+func (p *Pool) SubmitWithContext(taskCtx context.Context, task Task, opts ...SubmitOption) error {
+	j := acquireJob(task, opts)
+	j.ctx = taskCtx
+	if err := p.submit(j); err != ErrDiscarded {
+		return err
+	}
+	return nil
+}
+
+// mergeContext 返回一个派生自 parent 的上下文，它在 parent 或 other 任一结束时被取消。
+// 上下文中的值只从 parent 继承；返回的 cancel 必须被调用以释放资源。
+func mergeContext(parent, other context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	stop := context.AfterFunc(other, func() {
+		cancel(context.Cause(other))
+	})
+	return ctx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
@@ -0,0 +1,168 @@
+// Package cron 提供基于 cron 表达式的周期调度，把到期的任务提交到 gopoolx.Pool 中执行，
+// 使周期任务与普通任务共享同一套并发控制、重试、panic 恢复与错误收集。
+//
+// 典型用法：
+//
+//	pool := gopoolx.New(4)
+//	pool.Run(ctx)
+//
+//	c := cron.New(pool)
+//	c.Schedule("*/5 * * * *", task)
+//	c.Start(ctx)
+//	defer c.Stop()
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyin49954/gopoolx"
+)
+
+// EntryID 标识一个已注册的调度项，可用于 Remove。
+type EntryID int
+
+// entry 是一个已注册的调度项。
+type entry struct {
+	schedule Schedule
+	task     gopoolx.Task
+	opts     []gopoolx.SubmitOption
+	// stop 在调度项被移除或调度器停止时关闭
+	stop chan struct{}
+}
+
+// Scheduler 按 cron 规则将任务提交到 Pool。
+// 调度器只负责在触发时间提交任务，任务本身在池的 worker 中执行。
+type Scheduler struct {
+	pool gopoolx.Submitter
+
+	mu      sync.Mutex
+	entries map[EntryID]*entry
+	nextID  EntryID
+	// ctx 为 Start 传入的上下文，未启动或已停止时为 nil
+	ctx context.Context
+	// stopping 表示 Stop 正在等待调度 goroutine 退出，此期间不再启动新的调度 goroutine
+	stopping bool
+	// wg 等待所有调度 goroutine 退出，只在持有 mu 且未在停止时 Add
+	wg sync.WaitGroup
+}
+
+// New 创建一个将任务提交到 pool 的调度器。pool 通常是 *gopoolx.Pool，
+// 测试中也可以传入 pooltest.Recorder，逐个检查和执行被触发的任务。
+func New(pool gopoolx.Submitter) *Scheduler {
+	return &Scheduler{
+		pool:    pool,
+		entries: make(map[EntryID]*entry),
+	}
+}
+
+// Schedule 解析 cron 表达式 spec 并注册任务，返回调度项 ID。
+// opts 会在每次提交时透传给 Pool.Submit。
+// 调度器已启动时，新注册的任务立即开始调度。
+func (s *Scheduler) Schedule(spec string, task gopoolx.Task, opts ...gopoolx.SubmitOption) (EntryID, error) {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return 0, err
+	}
+	return s.ScheduleFunc(schedule, task, opts...), nil
+}
+
+// ScheduleFunc 使用已解析（或自定义）的 Schedule 注册任务，返回调度项 ID。
+func (s *Scheduler) ScheduleFunc(schedule Schedule, task gopoolx.Task, opts ...gopoolx.SubmitOption) EntryID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	id := s.nextID
+	e := &entry{
+		schedule: schedule,
+		task:     task,
+		opts:     opts,
+		stop:     make(chan struct{}),
+	}
+	s.entries[id] = e
+	if s.ctx != nil {
+		s.startEntry(s.ctx, e)
+	}
+	return id
+}
+
+// Remove 移除一个调度项，之后不会再提交该任务；已提交的执行不受影响。
+func (s *Scheduler) Remove(id EntryID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[id]; ok {
+		close(e.stop)
+		delete(s.entries, id)
+	}
+}
+
+// Start 启动所有调度项。ctx 结束或调用 Stop 后停止调度。
+// 已启动时重复调用 Start 不会产生效果；Stop 返回后可以再次 Start。
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx != nil || s.stopping {
+		return
+	}
+	s.ctx = ctx
+	for _, e := range s.entries {
+		s.startEntry(ctx, e)
+	}
+}
+
+// Stop 停止所有调度项，并等待调度 goroutine 退出。之后注册的任务要等下一次 Start 才开始调度。
+// Stop 不会等待已提交到池中的任务，需要时请调用 Pool.Wait。
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	for id, e := range s.entries {
+		close(e.stop)
+		delete(s.entries, id)
+	}
+	s.ctx = nil
+	s.stopping = true
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	s.mu.Lock()
+	s.stopping = false
+	s.mu.Unlock()
+}
+
+// startEntry 为调度项启动一个调度 goroutine，调用方需持有锁，且调度器已启动、未在停止。
+func (s *Scheduler) startEntry(ctx context.Context, e *entry) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, e)
+	}()
+}
+
+// run 是单个调度项的调度循环：等待到下一次触发时间后提交任务。
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		next := e.schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer.Reset(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.stop:
+			return
+		case <-timer.C:
+			// 提交失败（队列满、池已关闭等）由池按自身策略处理并记录
+			_ = s.pool.Submit(e.task, e.opts...)
+		}
+	}
+}
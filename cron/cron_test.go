@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyin49954/gopoolx"
+)
+
+// countingPool 只记录提交次数，不执行任务。
+type countingPool struct{ n atomic.Int32 }
+
+func (p *countingPool) Submit(gopoolx.Task, ...gopoolx.SubmitOption) error {
+	p.n.Add(1)
+	return nil
+}
+
+// every 是每隔 d 触发一次的调度规则。
+type every time.Duration
+
+func (d every) Next(t time.Time) time.Time { return t.Add(time.Duration(d)) }
+
+func noop(context.Context) error { return nil }
+
+func TestScheduleAfterStop(t *testing.T) {
+	pool := &countingPool{}
+	s := New(pool)
+	s.Start(context.Background())
+	s.Stop()
+
+	s.ScheduleFunc(every(time.Millisecond), noop)
+	time.Sleep(20 * time.Millisecond)
+	if n := pool.n.Load(); n != 0 {
+		t.Fatalf("stopped scheduler submitted %d tasks", n)
+	}
+
+	// 再次 Start 后开始调度停止期间注册的任务
+	s.Start(context.Background())
+	defer s.Stop()
+	deadline := time.Now().Add(time.Second)
+	for pool.n.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("restarted scheduler never submitted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStopRacingSchedule(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		s := New(&countingPool{})
+		s.Start(context.Background())
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					s.ScheduleFunc(every(time.Millisecond), noop)
+				}
+			}()
+		}
+		s.Stop()
+		wg.Wait()
+		s.Stop()
+	}
+}
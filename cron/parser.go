@@ -0,0 +1,217 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 描述一个周期调度规则。
+type Schedule interface {
+	// Next 返回严格晚于 t 的下一次触发时间；不存在时返回零值。
+	Next(t time.Time) time.Time
+}
+
+// bounds 描述一个字段的取值范围以及可用的名称别名。
+// limit 大于 max 时，(max, limit] 中的取值只能显式写出，不属于 * 或 n/step 展开的范围。
+type bounds struct {
+	min, max int
+	limit    int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = bounds{min: 0, max: 59}
+	hourBounds   = bounds{min: 0, max: 23}
+	domBounds    = bounds{min: 1, max: 31}
+	monthBounds  = bounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 星期字段允许显式写 7 表示周日，解析完成后折叠到 0
+	dowBounds = bounds{min: 0, max: 6, limit: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors 是常用调度规则的简写。
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse 解析标准的 5 字段 cron 表达式：分 时 日 月 周。
+// 每个字段支持 *、?、数字、范围（1-5）、步长（*/5、10-30/2）、列表（1,3,5），
+// 月份和星期支持英文缩写（JAN、MON）；星期字段中显式写出的 7（如 7、5-7）同样表示周日，
+// 而 * 与 n/step 只展开 0-6。
+// 日、周字段都受限制时两者取"或"关系；以 * 或 ? 开头的字段（包括 */2）视为不受限制，与 Vixie cron 相同。
+// 另外支持 @yearly、@monthly、@weekly、@daily、@hourly 等简写。
+// 计算触发时间时使用传入时间所在的时区。
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d in %q", len(fields), spec)
+	}
+
+	var (
+		s   specSchedule
+		err error
+	)
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = isWildcard(fields[2])
+	s.dowStar = isWildcard(fields[4])
+	return &s, nil
+}
+
+// isWildcard 判断字段是否以通配符开头，此时日、周字段之间不按"或"关系合并。
+func isWildcard(field string) bool {
+	return strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+}
+
+// parseField 将一个字段解析为位图，第 i 位为 1 表示取值 i 命中。
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		v, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= v
+	}
+	return bits, nil
+}
+
+// parseRange 解析列表中的单个元素：*、n、a-b，以及可选的 /step 后缀。
+func parseRange(part string, b bounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	lo, hi := b.min, b.max
+	switch {
+	case rangePart == "*" || rangePart == "?":
+	default:
+		loStr, hiStr, isRange := strings.Cut(rangePart, "-")
+		var err error
+		if lo, err = parseValue(loStr, b); err != nil {
+			return 0, err
+		}
+		hi = lo
+		if isRange {
+			if hi, err = parseValue(hiStr, b); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			// "n/step" 表示从 n 开始直到字段最大值
+			hi = b.max
+		}
+	}
+	if lo > hi {
+		return 0, fmt.Errorf("cron: invalid range %q", part)
+	}
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("cron: invalid step %q", part)
+		}
+	}
+
+	var bits uint64
+	for i := lo; i <= hi; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+// parseValue 解析单个取值，支持数字和名称别名，并检查取值范围。
+func parseValue(s string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("cron: invalid value %q", s)
+	}
+	if hi := max(b.max, b.limit); v < b.min || v > hi {
+		return 0, fmt.Errorf("cron: value %d out of range [%d, %d]", v, b.min, hi)
+	}
+	return v, nil
+}
+
+// specSchedule 是由 cron 表达式解析得到的调度规则，各字段以位图表示。
+type specSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar / dowStar 表示日、周字段是否为通配符，
+	// 两者都有限制时按标准 cron 语义取"或"关系
+	domStar, dowStar bool
+}
+
+// maxSearchYears 限制 Next 向后搜索的年数，避免不可能的表达式（如 2 月 30 日）死循环。
+const maxSearchYears = 5
+
+// Next 实现 Schedule，返回严格晚于 t 的下一次触发时间（精确到分钟）。
+func (s *specSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.Year() + maxSearchYears
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断 t 所在日期是否命中日、周字段。
+func (s *specSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
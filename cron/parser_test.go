@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// values 返回位图 bits 中命中的取值。
+func values(bits uint64) []int {
+	var vs []int
+	for i := 0; i < 64; i++ {
+		if bits&(1<<uint(i)) != 0 {
+			vs = append(vs, i)
+		}
+	}
+	return vs
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		spec   string
+		minute []int
+		dow    []int
+	}{
+		{"*/15 * * * *", []int{0, 15, 30, 45}, []int{0, 1, 2, 3, 4, 5, 6}},
+		{"10-30/10 * * * *", []int{10, 20, 30}, []int{0, 1, 2, 3, 4, 5, 6}},
+		{"50/5 * * * *", []int{50, 55}, []int{0, 1, 2, 3, 4, 5, 6}},
+		{"0 * * * 1/2", []int{0}, []int{1, 3, 5}},
+		{"0 * * * */2", []int{0}, []int{0, 2, 4, 6}},
+		{"0 * * * */3", []int{0}, []int{0, 3, 6}},
+		{"0 * * * 7", []int{0}, []int{0}},
+		{"0 * * * 5-7", []int{0}, []int{0, 5, 6}},
+		{"0 * * * 0-7", []int{0}, []int{0, 1, 2, 3, 4, 5, 6}},
+		{"0 * * * 1,7", []int{0}, []int{0, 1}},
+		{"0 * * * mon-fri", []int{0}, []int{1, 2, 3, 4, 5}},
+		{"0 * * * SUN", []int{0}, []int{0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			sched, err := Parse(tc.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := sched.(*specSchedule)
+			if got := values(s.minute); !slices.Equal(got, tc.minute) {
+				t.Errorf("minute = %v, want %v", got, tc.minute)
+			}
+			if got := values(s.dow); !slices.Equal(got, tc.dow) {
+				t.Errorf("dow = %v, want %v", got, tc.dow)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"30-10 * * * *",
+		"* * * * foo",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// 2026-10-14 是周三
+	from := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"30 12 * * *", time.Date(2026, 10, 14, 12, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1/2", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		// 日、周都受限制时取"或"：最近的周一早于下一个 1 日
+		{"0 0 1 * 1", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		// 以 * 开头的周字段不受限制，改取"与"：逢双数星期的 1 日，即周日 11 月 1 日
+		{"0 0 1 * */2", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			sched, err := Parse(tc.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sched.Next(from); !got.Equal(tc.want) {
+				t.Errorf("Next = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
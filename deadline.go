@@ -0,0 +1,29 @@
+package gopoolx
+
+import (
+	"context"
+	"time"
+)
+
+// WithDeadlinePropagation 让带截止时间的任务（SubmitWithContext 传入的 ctx 设有 deadline）
+// 在执行时把实际排队时长从截止时间中扣除：任务的 ctx 截止于原截止时间减去排队时长。
+// 排队越久，留给下游调用的时间越短，任务不会再发起注定来不及完成的下游请求；
+// 扣除后已经过期的任务不再执行，与出队时 ctx 已结束的任务一样以 context.DeadlineExceeded 被跳过。
+func WithDeadlinePropagation() Option {
+	return func(o *Options) {
+		o.deadlinePropagation = true
+	}
+}
+
+// shrinkDeadline 按排队时长 waited 收紧任务 j 的截止时间，由启用 WithDeadlinePropagation 的池调用。
+// 返回的 cancel 必须被调用；任务没有截止时间时 ctx 原样返回。
+func (p *Pool) shrinkDeadline(ctx context.Context, j *job, waited time.Duration) (context.Context, context.CancelFunc) {
+	if j.ctx == nil || waited <= 0 {
+		return ctx, func() {}
+	}
+	deadline, ok := j.ctx.Deadline()
+	if !ok {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, deadline.Add(-waited))
+}
@@ -0,0 +1,50 @@
+package gopoolx
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultPool 是包级默认池，首次使用时才创建；defaultPoolMu 保护其创建与替换。
+var (
+	defaultPool   atomic.Pointer[Pool]
+	defaultPoolMu sync.Mutex
+)
+
+// Default 返回包级默认池。未通过 SetDefault 设置时，首次调用会创建一个
+// worker 数等于 GOMAXPROCS 的池并以 context.Background() 启动。
+// 默认池在进程内共享，不应对它调用 Wait。
+func Default() *Pool {
+	if p := defaultPool.Load(); p != nil {
+		return p
+	}
+	defaultPoolMu.Lock()
+	defer defaultPoolMu.Unlock()
+	if p := defaultPool.Load(); p != nil {
+		return p
+	}
+	p := New(runtime.GOMAXPROCS(0))
+	p.Run(context.Background())
+	defaultPool.Store(p)
+	return p
+}
+
+// SetDefault 将 p 设为包级默认池并返回之前的默认池（可能为 nil）。
+// p 需由调用方自行 Run；传入 nil 表示恢复为首次使用时自动创建。
+func SetDefault(p *Pool) *Pool {
+	defaultPoolMu.Lock()
+	defer defaultPoolMu.Unlock()
+	return defaultPool.Swap(p)
+}
+
+// Go 将任务提交到包级默认池，语义与 Pool.Submit 相同。
+// 小程序无需任何初始化即可获得受限的并发度：
+//
+//	for _, f := range files {
+//		gopoolx.Go(func(ctx context.Context) error { return process(f) })
+//	}
+func Go(task Task, opts ...SubmitOption) error {
+	return Default().Submit(task, opts...)
+}
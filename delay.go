@@ -0,0 +1,245 @@
+package gopoolx
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// delayedJob 是等待到期后才入队执行的任务。
+type delayedJob struct {
+	// at 是任务允许被执行的最早时间
+	at time.Time
+	// j 是到期后要入队的任务
+	j *job
+}
+
+// delayHeap 是按到期时间排序的最小堆，实现 container/heap.Interface。
+type delayHeap []*delayedJob
+
+func (h delayHeap) Len() int           { return len(h) }
+func (h delayHeap) Less(i, k int) bool { return h[i].at.Before(h[k].at) }
+func (h delayHeap) Swap(i, k int)      { h[i], h[k] = h[k], h[i] }
+
+func (h *delayHeap) Push(x any) { *h = append(*h, x.(*delayedJob)) }
+
+func (h *delayHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// delayQueue 保存所有尚未到期的延迟任务，由 Run 启动的调度 goroutine 负责消费。
+type delayQueue struct {
+	mu    sync.Mutex
+	items delayHeap
+	// wake 用于在新任务加入时唤醒调度 goroutine，重新计算最近的到期时间
+	wake chan struct{}
+	// closed 表示调度 goroutine 已随池的 ctx 结束而退出
+	closed bool
+}
+
+// newDelayQueue 创建一个空的延迟队列。
+func newDelayQueue() *delayQueue {
+	return &delayQueue{
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// SubmitAfter 提交一个延迟任务，任务在 d 时间之后才会进入队列等待执行。
+// 延迟期间任务同样计入 Wait 的等待范围，以及 WithMaxPending 的在途名额；
+// 到期入队时按队列满策略处理。
+// 若 Run 的 ctx 在任务到期前结束，尚未到期的任务会被直接取消：不会执行，也不计入错误。
+func (p *Pool) SubmitAfter(d time.Duration, task Task, opts ...SubmitOption) error {
+	return p.SubmitAt(p.opts.clock.Now().Add(d), task, opts...)
+}
+
+// SubmitAt 提交一个定时任务，任务在时间 t 之后才会进入队列等待执行。
+// 语义与 SubmitAfter 相同；t 早于当前时间时任务会尽快入队。
+func (p *Pool) SubmitAt(t time.Time, task Task, opts ...SubmitOption) error {
+	j := newJob(task, opts)
+	p.track(j)
+	if err := p.admit(j); err != nil {
+		p.settleRejected(j, err)
+		if err == ErrDiscarded {
+			return nil
+		}
+		return err
+	}
+
+	if p.opts.synchronous {
+		return p.enqueue(j)
+	}
+
+	if !p.pushDelayed(j, t) {
+		p.reject(j, ErrPoolClosed)
+		return ErrPoolClosed
+	}
+	return nil
+}
+
+// pushDelayed 将已登记计数的任务 j 加入延迟队列，在 t 之后入队；
+// 调度 goroutine 已退出时返回 false，由调用方释放任务。
+func (p *Pool) pushDelayed(j *job, t time.Time) bool {
+	dq := p.delayed
+	dq.mu.Lock()
+	if dq.closed {
+		dq.mu.Unlock()
+		return false
+	}
+	heap.Push(&dq.items, &delayedJob{at: t, j: j})
+	dq.mu.Unlock()
+
+	// 非阻塞唤醒：调度 goroutine 已有待处理的唤醒信号时无需重复发送
+	select {
+	case dq.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// runDelayed 是延迟任务的调度循环，由 Run 启动。
+// 它在最近一个任务到期时将所有到期任务入队；ctx 结束时取消全部未到期任务，
+// Wait 关闭池时随之退出。
+func (p *Pool) runDelayed(ctx context.Context) {
+	dq := p.delayed
+	timer := p.opts.clock.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	// 队列已满时入队可能阻塞，交给单独的 goroutine 按到期顺序入队，避免推迟之后到期的任务
+	h := &dueHandoff{wake: make(chan struct{}, 1)}
+	go p.forwardDue(h)
+	defer h.stop()
+
+	for {
+		dq.mu.Lock()
+		var due []*job
+		now := p.opts.clock.Now()
+		for len(dq.items) > 0 && !dq.items[0].at.After(now) {
+			due = append(due, heap.Pop(&dq.items).(*delayedJob).j)
+		}
+		wait := time.Duration(-1)
+		if len(dq.items) > 0 {
+			wait = dq.items[0].at.Sub(now)
+		}
+		dq.mu.Unlock()
+
+		for _, j := range due {
+			// 等待重试的任务已经执行过，不再参与合并
+			if j.coalesce != nil && j.attempts == 0 {
+				p.fireCoalesced(j)
+				continue
+			}
+			// 排队期间已被取消的任务无需再入队
+			if j.handle != nil && j.handle.Status() != TaskQueued {
+				continue
+			}
+			h.push(j)
+		}
+
+		var timerC <-chan time.Time
+		if wait >= 0 {
+			timer.Reset(wait)
+			timerC = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			p.cancelDelayed()
+			return
+		case <-p.closed:
+			p.cancelDelayed()
+			return
+		case <-dq.wake:
+		case <-timerC:
+		}
+		timer.Stop()
+	}
+}
+
+// cancelDelayed 关闭延迟队列，并释放所有尚未到期任务占用的计数。
+func (p *Pool) cancelDelayed() {
+	dq := p.delayed
+	dq.mu.Lock()
+	items := dq.items
+	dq.items = nil
+	dq.closed = true
+	dq.mu.Unlock()
+
+	for _, item := range items {
+		// 等待重试的任务已经开始执行，以 ErrCanceled 结束并交付结果
+		if item.j.attempts > 0 {
+			p.skip(item.j, TaskCanceled, ErrCanceled, &p.stats.skipped)
+			continue
+		}
+		if item.j.coalesce != nil {
+			p.cancelCoalesced(item.j)
+			continue
+		}
+		if p.settleRejected(item.j, ErrCanceled) {
+			p.done(item.j)
+		}
+	}
+}
+
+// dueHandoff 是调度 goroutine 交给 forwardDue 入队的到期任务列表，长度不受限制：
+// 其中的任务都已登记计数，数量不会超过已提交的延迟任务。
+type dueHandoff struct {
+	mu      sync.Mutex
+	jobs    []*job
+	stopped bool
+	// wake 在加入任务或停止时唤醒 forwardDue
+	wake chan struct{}
+}
+
+// push 将到期任务 j 加入列表。
+func (h *dueHandoff) push(j *job) {
+	h.mu.Lock()
+	h.jobs = append(h.jobs, j)
+	h.mu.Unlock()
+	h.signal()
+}
+
+// stop 通知 forwardDue 入队剩余任务后退出；之后不能再调用 push。
+func (h *dueHandoff) stop() {
+	h.mu.Lock()
+	h.stopped = true
+	h.mu.Unlock()
+	h.signal()
+}
+
+// signal 以非阻塞方式唤醒 forwardDue。
+func (h *dueHandoff) signal() {
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take 取出列表中的全部任务，stopped 表示调度 goroutine 已退出。
+func (h *dueHandoff) take() (jobs []*job, stopped bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	jobs, h.jobs = h.jobs, nil
+	return jobs, h.stopped
+}
+
+// forwardDue 按到期顺序将 h 中的任务入队，直到调度 goroutine 退出且列表为空。
+// Run 的 ctx 结束后入队失败的任务由 enqueue 以 ErrPoolClosed 释放。
+func (p *Pool) forwardDue(h *dueHandoff) {
+	for {
+		jobs, stopped := h.take()
+		for _, j := range jobs {
+			p.enqueue(j)
+		}
+		if stopped {
+			return
+		}
+		<-h.wake
+	}
+}
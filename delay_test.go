@@ -0,0 +1,37 @@
+package gopoolx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestDelayedFullQueueDoesNotStallTimer 到期任务因队列已满而等待入队时，调度 goroutine 仍应继续取出之后到期的任务。
+func TestDelayedFullQueueDoesNotStallTimer(t *testing.T) {
+	p := newRunningPool(t, 1, WithQueueSize(1))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p.Submit(func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	p.Submit(func(context.Context) error { return nil })
+
+	// 队列已满，第一个到期的任务只能等待入队
+	p.SubmitAfter(time.Millisecond, func(context.Context) error { return nil })
+	time.Sleep(10 * time.Millisecond)
+	p.SubmitAfter(time.Millisecond, func(context.Context) error { return nil })
+	waitFor(t, func() bool {
+		p.delayed.mu.Lock()
+		defer p.delayed.mu.Unlock()
+		return len(p.delayed.items) == 0
+	})
+
+	close(release)
+	p.Wait()
+	if got := p.Stats().Succeeded; got != 4 {
+		t.Fatalf("Succeeded = %d, want 4", got)
+	}
+}
@@ -0,0 +1,316 @@
+package gopoolx
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// DispatchMode 定义任务从队列分派到 worker 的方式。
+type DispatchMode int
+
+const (
+	// DispatchShared 所有 worker 直接竞争同一个任务队列（默认）
+	DispatchShared DispatchMode = iota
+	// DispatchRoundRobin 由分派 goroutine 按轮转顺序把任务交给各 worker，跳过本地缓冲已满的 worker
+	DispatchRoundRobin
+	// DispatchLeastLoaded 由分派 goroutine 把任务交给已分派但未执行完的任务最少的 worker
+	DispatchLeastLoaded
+)
+
+// String 返回分派方式的可读名称。
+func (m DispatchMode) String() string {
+	switch m {
+	case DispatchShared:
+		return "shared"
+	case DispatchRoundRobin:
+		return "round-robin"
+	case DispatchLeastLoaded:
+		return "least-loaded"
+	default:
+		return "unknown"
+	}
+}
+
+// dispatchBuffer 是分派模式下每个 worker 本地通道的缓冲大小。
+// 缓冲保持很小，使积压的任务留在共享队列中，QueueLen 与队列满策略的语义不变
+// （OrderFIFOPerKey 下分派方可另外暂存至多与队列容量相同数量的任务，见 WithOrdering）。
+const dispatchBuffer = 1
+
+// WithDispatchMode 设置任务分派方式。默认的 DispatchShared 下 N 个 worker 在同一个通道上接收，
+// worker 很多且任务很短时该通道会成为争用热点；其他模式改由单个分派 goroutine 从队列取出任务，
+// 再经每个 worker 独占的通道交给它执行，每个通道只有一个发送方和一个接收方。
+// 分派方不会阻塞在某个忙碌的 worker 上：所有 worker 的本地通道都满时，它等待任意一个 worker 腾出空间。
+// 分派多了一次通道交接，核数或 worker 较少时通常比默认方式更慢，应以实际负载的基准测试为准。
+// 分派模式下 WithDequeueBatch 不生效。
+func WithDispatchMode(mode DispatchMode) Option {
+	return func(o *Options) {
+		o.dispatchMode = mode
+	}
+}
+
+// dispatchWorker 是分派模式下一个 worker 的本地通道与负载。
+type dispatchWorker struct {
+	ch chan *job
+	// ready 由所有 worker 共享，worker 从本地通道取走任务、腾出空间时通过它通知分派 goroutine
+	ready *dispatchReady
+	// load 是已分派给该 worker 但尚未执行完的任务数
+	load atomic.Int64
+	// backlog 是 OrderFIFOPerKey 下已分派给该 worker、但本地通道已满而暂存的任务，按分派顺序排列，
+	// 只由分派 goroutine 访问
+	backlog []*job
+}
+
+// runDispatched 以分派模式启动 worker 与分派 goroutine。
+func (p *Pool) runDispatched(ctx context.Context) {
+	ready := &dispatchReady{ch: make(chan struct{}, 1)}
+	workers := make([]*dispatchWorker, p.workers())
+	for i := range workers {
+		w := &dispatchWorker{ch: make(chan *job, dispatchBuffer), ready: ready}
+		workers[i] = w
+		go p.dispatchedWorker(ctx, w)
+	}
+	go p.dispatch(ctx, workers)
+}
+
+// dispatchReady 是分派 goroutine 等待 worker 腾出空间时使用的通知。
+type dispatchReady struct {
+	ch chan struct{}
+	// waiting 表示分派方正在等待，worker 只在这时发送通知，平时不产生额外的通道操作
+	waiting atomic.Bool
+}
+
+// notify 在分派方等待时通知它。
+func (r *dispatchReady) notify() {
+	if r.waiting.Load() {
+		select {
+		case r.ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// dispatcher 是分派 goroutine 的状态。分派只做非阻塞的发送：没有 worker 能接收时，
+// 未带键的任务暂留在 held，带键的任务暂存到负责它的 worker 的 backlog，
+// 分派方随后等待任意一个 worker 腾出空间，而不是阻塞在某一个忙碌的 worker 上。
+type dispatcher struct {
+	pool    *Pool
+	workers []*dispatchWorker
+	ready   *dispatchReady
+	// next 是轮转的起始位置
+	next int
+	// held 是等待任意一个 worker 空闲的未带键任务
+	held *job
+	// backlogged 是所有 worker 的 backlog 中的任务总数，达到 limit 后不再从队列取任务
+	backlogged int
+	limit      int
+}
+
+// dispatch 是分派 goroutine 的循环，直到 ctx 结束或任务通道关闭；
+// 退出时关闭所有 worker 的本地通道，worker 执行完已分派的任务后退出。
+// ctx 结束后仍在队列中的任务由 drain 处理，已取出但尚未交给 worker 的任务由分派方跳过。
+func (p *Pool) dispatch(ctx context.Context, workers []*dispatchWorker) {
+	d := &dispatcher{pool: p, workers: workers, ready: workers[0].ready, limit: max(cap(p.tasks), len(workers))}
+	defer func() {
+		for _, w := range workers {
+			close(w.ch)
+		}
+	}()
+
+	for {
+		// 没有暂存的任务时只需等待队列
+		if d.held == nil && d.backlogged == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case j, ok := <-p.tasks:
+				// 任务通道只在所有任务结束后关闭，此时没有暂存的任务
+				if !ok {
+					return
+				}
+				d.assign(j)
+			}
+			continue
+		}
+		if !d.wait(ctx) {
+			return
+		}
+	}
+}
+
+// wait 在有暂存任务时等待 worker 腾出空间，暂存未达上限时同时继续从队列取任务。
+// ctx 结束时跳过暂存的任务并返回 false。
+func (d *dispatcher) wait(ctx context.Context) bool {
+	// 先声明等待再重试一次，之后腾出空间的 worker 一定会发出通知
+	d.ready.waiting.Store(true)
+	defer d.ready.waiting.Store(false)
+	d.flush()
+	if d.held == nil && d.backlogged == 0 {
+		return true
+	}
+	// 暂存过多时先消化积压，让队列满策略重新生效
+	var tasks <-chan *job
+	if d.held == nil && d.backlogged < d.limit {
+		tasks = d.pool.tasks
+	}
+	select {
+	case <-ctx.Done():
+		d.abandon(ctx.Err())
+		return false
+	case <-d.ready.ch:
+	case j, ok := <-tasks:
+		if !ok {
+			return false
+		}
+		d.assign(j)
+	}
+	return true
+}
+
+// assign 把刚取出的任务 j 交给 worker，无法立即交出时暂存。
+func (d *dispatcher) assign(j *job) {
+	if w := d.pool.keyedWorker(d.workers, j); w != nil {
+		w.load.Add(1)
+		// 已有暂存的任务时排在它们之后，保持同一个 worker 上的分派顺序
+		if len(w.backlog) > 0 || !offer(w, j) {
+			w.backlog = append(w.backlog, j)
+			d.backlogged++
+		}
+		return
+	}
+	if !d.place(j) {
+		d.held = j
+	}
+}
+
+// place 把未带键的任务 j 交给按分派方式选出的、本地通道有空间的 worker，没有这样的 worker 时返回 false。
+func (d *dispatcher) place(j *job) bool {
+	w := d.pool.pickWorker(d.workers, &d.next)
+	if w == nil {
+		return false
+	}
+	w.load.Add(1)
+	if !offer(w, j) {
+		w.load.Add(-1)
+		return false
+	}
+	return true
+}
+
+// flush 尽可能把暂存的任务交给 worker。
+func (d *dispatcher) flush() {
+	if d.backlogged > 0 {
+		for _, w := range d.workers {
+			for len(w.backlog) > 0 && offer(w, w.backlog[0]) {
+				w.backlog[0] = nil
+				w.backlog = w.backlog[1:]
+				d.backlogged--
+			}
+			if len(w.backlog) == 0 {
+				w.backlog = nil
+			}
+		}
+	}
+	if d.held != nil && d.place(d.held) {
+		d.held = nil
+	}
+}
+
+// abandon 以 err 跳过所有已取出但尚未交给 worker 的任务。
+func (d *dispatcher) abandon(err error) {
+	p := d.pool
+	if d.held != nil {
+		p.addQueued(-1)
+		p.skip(d.held, TaskCanceled, err, &p.stats.skipped)
+		d.held = nil
+	}
+	for _, w := range d.workers {
+		for _, j := range w.backlog {
+			w.load.Add(-1)
+			p.addQueued(-1)
+			p.skip(j, TaskCanceled, err, &p.stats.skipped)
+		}
+		w.backlog = nil
+	}
+	d.backlogged = 0
+}
+
+// offer 以非阻塞方式把 j 放入 w 的本地通道，通道已满时返回 false。
+func offer(w *dispatchWorker, j *job) bool {
+	select {
+	case w.ch <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+// pickWorker 按分派方式选出下一个任务的 worker，只考虑本地通道还有空间的 worker：
+// 轮转模式返回从轮转位置起第一个有空间的 worker，最少负载模式返回其中负载最小的 worker。
+// 所有 worker 都没有空间时返回 nil。
+func (p *Pool) pickWorker(workers []*dispatchWorker, next *int) *dispatchWorker {
+	n := len(workers)
+	start := *next
+	if p.opts.dispatchMode == DispatchLeastLoaded {
+		*next = (start + 1) % n
+		// 从轮转位置开始比较，负载相同时任务仍会分散到各 worker
+		var best *dispatchWorker
+		for i := 0; i < n; i++ {
+			w := workers[(start+i)%n]
+			if len(w.ch) == cap(w.ch) {
+				continue
+			}
+			if best == nil || w.load.Load() < best.load.Load() {
+				best = w
+			}
+			if best.load.Load() == 0 {
+				break
+			}
+		}
+		return best
+	}
+	for i := 0; i < n; i++ {
+		if w := workers[(start+i)%n]; len(w.ch) < cap(w.ch) {
+			*next = (start + i + 1) % n
+			return w
+		}
+	}
+	return nil
+}
+
+// dispatchedWorker 是分派模式下的 worker，意外退出时由新的 worker 接管同一个本地通道。
+func (p *Pool) dispatchedWorker(ctx context.Context, w *dispatchWorker) {
+	var busy bool
+	p.supervise(func() { p.dispatchedLoop(ctx, w, &busy) }, func() {
+		// 被中断的任务不会再执行完，由这里归还它占用的负载
+		if busy {
+			w.load.Add(-1)
+		}
+		go p.dispatchedWorker(ctx, w)
+	})
+}
+
+// dispatchedLoop 执行本地通道中的任务，直到通道被关闭；busy 表示正在执行一个分派来的任务。
+func (p *Pool) dispatchedLoop(ctx context.Context, w *dispatchWorker, busy *bool) {
+	ctx = p.withWorkerBuffer(ctx)
+	defer p.startWorker(ctx)()
+
+	for {
+		// 与 worker 相同，优先执行溢出任务
+		if j := p.overflow.pop(); j != nil {
+			p.addQueued(-1)
+			p.work(ctx, j)
+			continue
+		}
+		j, ok := <-w.ch
+		if !ok {
+			return
+		}
+		// 本地通道腾出了空间，分派方可能正在等待
+		w.ready.notify()
+		p.addQueued(-1)
+		*busy = true
+		p.work(ctx, j)
+		*busy = false
+		w.load.Add(-1)
+	}
+}
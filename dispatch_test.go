@@ -0,0 +1,53 @@
+package gopoolx
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+// TestDispatchBusyWorker 一个 worker 被长任务占住时，分派方不应阻塞在它身上：
+// 只有已放入它本地缓冲的任务（至多 dispatchBuffer 个）需要等待，其余任务由其他 worker 执行。
+func TestDispatchBusyWorker(t *testing.T) {
+	for _, mode := range []DispatchMode{DispatchRoundRobin, DispatchLeastLoaded} {
+		t.Run(mode.String(), func(t *testing.T) {
+			p := newRunningPool(t, 2, WithDispatchMode(mode))
+			release := make(chan struct{})
+			defer close(release)
+			started := make(chan struct{})
+			p.Submit(func(context.Context) error {
+				close(started)
+				<-release
+				return nil
+			})
+			<-started
+
+			const n = 10
+			var ran atomic.Int32
+			for i := 0; i < n; i++ {
+				p.Submit(func(context.Context) error {
+					ran.Add(1)
+					return nil
+				})
+			}
+			waitFor(t, func() bool { return ran.Load() >= n-dispatchBuffer })
+		})
+	}
+}
+
+func BenchmarkDispatch(b *testing.B) {
+	for _, mode := range []DispatchMode{DispatchShared, DispatchRoundRobin, DispatchLeastLoaded} {
+		b.Run(mode.String(), func(b *testing.B) {
+			p := New(runtime.GOMAXPROCS(0), WithQueueSize(1024), WithDispatchMode(mode), WithReentrantPolicy(ReentrantBlock))
+			go p.Run(context.Background())
+			task := func(context.Context) error { return nil }
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p.Submit(task)
+			}
+			p.Wait()
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package gopoolx
+
+import "context"
+
+// WithEphemeralWorkers 让每个任务在新启动的 goroutine 中执行：worker 只作为并发名额，取到任务后启动一个
+// goroutine 执行它并等待其结束，池因此相当于一个限制并发数的 goroutine 启动器。
+//   - 偶尔出现的深递归任务使 goroutine 栈增长后，栈随执行它的 goroutine 一起释放，不会被长期存活的 worker 保留
+//   - 任务调用 runtime.Goexit 只结束它自己的 goroutine：任务照常以 ErrWorkerExited 结束，worker 无需被替换，
+//     也不计入 Stats().WorkerRestarts；逃出任务的 panic 仍交给 worker 处理（见 WithOnWorkerRestart）
+//
+// 代价是每个任务多一次 goroutine 启动与同步，适合任务本身较重、或调用了不受控的第三方代码的池。
+// 内联执行（WithInlineThreshold）与 ReentrantCallerRuns 等在调用方中执行的任务不受影响。
+func WithEphemeralWorkers() Option {
+	return func(o *Options) {
+		o.ephemeralWorkers = true
+	}
+}
+
+// work 在 worker 中执行出队的任务 j；启用 WithEphemeralWorkers 时改为在新的 goroutine 中执行并等待它结束。
+func (p *Pool) work(ctx context.Context, j *job) {
+	if !p.opts.ephemeralWorkers {
+		p.exec(ctx, j)
+		return
+	}
+	// escaped 是逃出执行 goroutine 的 panic，在 worker 中重新抛出，使 worker 的替换逻辑照常生效
+	var (
+		escaped  any
+		panicked bool
+	)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if p.opts.customRecovery {
+				return
+			}
+			if r := recover(); r != nil {
+				escaped, panicked = r, true
+			}
+		}()
+		p.exec(ctx, j)
+	}()
+	<-done
+	if panicked {
+		panic(escaped)
+	}
+}
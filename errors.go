@@ -1,13 +1,69 @@
 package gopoolx
 
-import "sync"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
 
-// ErrorCollector 用于在并发环境下收集任务执行错误。
-// 通过内部互斥锁保证在多 goroutine 下安全地写入和读取错误切片。
+// errorShards 是 ErrorCollector 的分片数。
+const errorShards = 16
+
+// ErrorCollector 用于在并发环境下收集任务执行错误，零值即可使用，也可以脱离池单独使用，
+// 作为任意并发代码中的错误汇总工具（见 Addf、Wrap、Merge 与 ErrOrNil）。
+// 错误按写入顺序编号后分散到多个分片中，每个分片有独立的互斥锁，
+// 大量任务同时失败时 worker 不会在同一把锁上排队；读取时再按编号合并，
+// 因此 Errors 返回的顺序与写入顺序一致。
 type ErrorCollector struct {
+	// seq 为每个写入的错误分配递增编号，同时决定其所在分片
+	seq    atomic.Uint64
+	shards [errorShards]errorShard
+
+	// dedup 表示只保留每种错误信息的第一次出现，见 WithErrorDedup
+	dedup bool
+	// limit 是保留详细错误的上限，0 表示不限制；summarize 表示超出上限的错误仍按信息计数，
+	// 见 WithErrorCollection
+	limit     int
+	summarize bool
+	// counts 保存错误信息到出现次数（*atomic.Int64）的映射，仅在 dedup 或 summarize 时使用
+	counts sync.Map
+	// total 是写入的错误总数，dropped 是因超出 limit 或重复而未保留详情的错误数
+	total   atomic.Uint64
+	dropped atomic.Uint64
+
+	// watchers 是正在进行的 follow 迭代数，为 0 时 Add 无需通知；
+	// notify 在有新错误写入时被关闭并置空，由等待方按需创建
+	watchers atomic.Int32
+	notifyMu sync.Mutex
+	notify   chan struct{}
+}
+
+// newErrorCollector 按池的配置（WithErrorDedup、WithErrorCollection）创建错误收集器。
+func newErrorCollector(o *Options) *ErrorCollector {
+	return &ErrorCollector{
+		dedup:     o.errorDedup,
+		limit:     o.errorLimit,
+		summarize: o.errorSummarize,
+	}
+}
+
+// errorShard 是 ErrorCollector 的一个分片。
+type errorShard struct {
 	mu sync.Mutex
-	// errs 存放所有收集到的错误
-	errs []error
+	// errs 存放落入该分片的错误
+	errs []seqError
+	// 填充到独立的缓存行，避免相邻分片之间的伪共享
+	_ [32]byte
+}
+
+// seqError 是带写入编号的错误。
+type seqError struct {
+	seq uint64
+	err error
 }
 
 // Add 将一个错误加入收集器。
@@ -16,15 +72,171 @@ func (e *ErrorCollector) Add(err error) {
 	if err == nil {
 		return
 	}
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.errs = append(e.errs, err)
+	e.total.Add(1)
+	if e.counting() {
+		v, loaded := e.counts.LoadOrStore(err.Error(), new(atomic.Int64))
+		v.(*atomic.Int64).Add(1)
+		if loaded && e.dedup {
+			e.dropped.Add(1)
+			return
+		}
+	}
+	seq := e.seq.Add(1)
+	// 超出上限后只计数，内存占用不再增长
+	if e.limit > 0 && seq > uint64(e.limit) {
+		e.dropped.Add(1)
+		return
+	}
+	s := &e.shards[seq%errorShards]
+	s.mu.Lock()
+	s.errs = append(s.errs, seqError{seq: seq, err: err})
+	s.mu.Unlock()
+
+	if e.watchers.Load() > 0 {
+		e.notifyMu.Lock()
+		if e.notify != nil {
+			close(e.notify)
+			e.notify = nil
+		}
+		e.notifyMu.Unlock()
+	}
+}
+
+// Addf 按 format 与 args 构造错误（同 fmt.Errorf，支持 %w）并加入收集器。
+func (e *ErrorCollector) Addf(format string, args ...any) {
+	e.Add(fmt.Errorf(format, args...))
+}
+
+// Wrap 为 err 附加上下文 msg（"msg: err"，仍可用 errors.Is / errors.As 匹配 err）后加入收集器，
+// err 为 nil 时不做任何事，便于直接包裹返回错误的调用：c.Wrap(f.Close(), "close report")。
+func (e *ErrorCollector) Wrap(err error, msg string) {
+	if err == nil {
+		return
+	}
+	e.Add(fmt.Errorf("%s: %w", msg, err))
 }
 
-// Errors 返回一个包含已收集错误的切片副本。
+// Merge 将 other 中已收集的错误按顺序加入 e，other 不受影响。两个收集器都可以在合并期间
+// 被并发写入，合并只包含调用时 other 中已有的错误；e 的去重与上限配置照常生效。
+// other 为 nil 或就是 e 时不做任何事。
+func (e *ErrorCollector) Merge(other *ErrorCollector) {
+	if other == nil || other == e {
+		return
+	}
+	for _, err := range other.Errors() {
+		e.Add(err)
+	}
+}
+
+// ErrOrNil 在没有错误时返回 nil，否则以 errors.Join 合并所有已收集的错误返回，
+// 便于在函数末尾直接 return c.ErrOrNil()。
+func (e *ErrorCollector) ErrOrNil() error {
+	return errors.Join(e.Errors()...)
+}
+
+// Errors 返回一个包含已收集错误的切片副本，按写入顺序排列，没有错误时返回 nil。
 // 返回副本是为了避免调用方修改内部状态。
 func (e *ErrorCollector) Errors() []error {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	return append([]error(nil), e.errs...)
+	var all []seqError
+	for i := range e.shards {
+		s := &e.shards[i]
+		s.mu.Lock()
+		all = append(all, s.errs...)
+		s.mu.Unlock()
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	slices.SortFunc(all, func(a, b seqError) int {
+		switch {
+		case a.seq < b.seq:
+			return -1
+		case a.seq > b.seq:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	errs := make([]error, len(all))
+	for i, se := range all {
+		errs[i] = se.err
+	}
+	return errs
+}
+
+// follow 返回一个迭代器：先产出已收集的错误，再在新错误写入时继续产出，
+// 直到 done 被关闭且已写入的错误全部产出。各分片分别记录读取位置，每个错误只会被复制一次；
+// 并发写入时产出顺序大致与写入顺序一致。
+func (e *ErrorCollector) follow(done <-chan struct{}) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		e.watchers.Add(1)
+		defer e.watchers.Add(-1)
+
+		var cursors [errorShards]int
+		for {
+			// 先取得通知通道再读取，读取之后写入的错误一定会关闭该通道，不会漏掉唤醒
+			e.notifyMu.Lock()
+			if e.notify == nil {
+				e.notify = make(chan struct{})
+			}
+			notify := e.notify
+			e.notifyMu.Unlock()
+
+			for _, se := range e.since(&cursors) {
+				if !yield(se.err) {
+					return
+				}
+			}
+			select {
+			case <-notify:
+			case <-done:
+				for _, se := range e.since(&cursors) {
+					if !yield(se.err) {
+						return
+					}
+				}
+				return
+			}
+		}
+	}
+}
+
+// since 返回各分片中读取位置之后的错误（按编号排序），并推进读取位置。
+func (e *ErrorCollector) since(cursors *[errorShards]int) []seqError {
+	var fresh []seqError
+	for i := range e.shards {
+		s := &e.shards[i]
+		s.mu.Lock()
+		fresh = append(fresh, s.errs[cursors[i]:]...)
+		cursors[i] = len(s.errs)
+		s.mu.Unlock()
+	}
+	slices.SortFunc(fresh, func(a, b seqError) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	return fresh
+}
+
+// counting 判断是否需要按错误信息计数。
+func (e *ErrorCollector) counting() bool {
+	return e.dedup || e.summarize
+}
+
+// Summary 返回每种错误信息（err.Error()）到其出现次数的映射，没有错误时返回空映射。
+// 启用去重时，重复的错误不会出现在 Errors 中，但仍计入这里的次数；
+// 限制了详细错误数时，只有启用 SummarizeRest 才会计入超出上限的错误。
+func (e *ErrorCollector) Summary() map[string]int {
+	summary := make(map[string]int)
+	if e.counting() {
+		e.counts.Range(func(k, v any) bool {
+			summary[k.(string)] = int(v.(*atomic.Int64).Load())
+			return true
+		})
+		return summary
+	}
+	for _, err := range e.Errors() {
+		summary[err.Error()]++
+	}
+	return summary
 }
@@ -0,0 +1,14 @@
+package gopoolx
+
+import "context"
+
+// WithFallback 为任务设置降级函数：重试耗尽（或被熔断、限流等待失败等）后仍失败时，
+// 以最终错误调用 fn，并以 fn 的返回值取代该错误，例如刷新缓存的任务失败时继续提供旧数据。
+// fn 返回 nil 时任务视为成功，并计入 Stats().Degraded；返回非 nil 时以该错误结束任务。
+// 任务被 WithHardTimeout 放弃时不会执行降级（任务本身仍在运行）。
+// fn 在执行任务的 worker 中运行，收到的 ctx 与任务相同。
+func WithFallback(fn func(ctx context.Context, err error) error) SubmitOption {
+	return func(o *submitOptions) {
+		o.fallback = fn
+	}
+}
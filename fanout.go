@@ -0,0 +1,78 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// FanOut 为 inputs 中的每个元素提交一个任务到 pool，并按输入顺序收集结果。
+// 说明：
+//   - results[i] 对应 inputs[i]；失败的元素在 results 中为零值
+//   - err 按输入顺序聚合所有失败元素的错误（errors.Join），全部成功时为 nil
+//   - fn 收到的 ctx 在 pool 的 Run ctx 或调用方 ctx 任一结束时取消，其中的值取自调用方 ctx；
+//     调用方 ctx 结束后，尚未开始的元素不再执行并以 ctx.Err() 失败，它们被池计为跳过，
+//     不会被重试，也不计入 pool.Errors()
+//   - fn 返回的错误同样会触发池的重试，并计入 pool.Errors()
+//
+// FanOut 只等待本次提交的任务，不会关闭池，可在同一个池上并发调用。
+func FanOut[T, R any](
+	ctx context.Context,
+	pool *Pool,
+	inputs []T,
+	fn func(ctx context.Context, in T) (R, error),
+) ([]R, error) {
+	results := make([]R, len(inputs))
+	errs := make([]error, len(inputs))
+	runEach(ctx, pool, slices.All(inputs), func(ctx context.Context, i int, in T) error {
+		res, err := fn(ctx, in)
+		if err != nil {
+			return err
+		}
+		results[i] = res
+		return nil
+	}, func(i int, err error) {
+		errs[i] = err
+	})
+	return results, errors.Join(errs...)
+}
+
+// runEach 为 items 产出的每个（下标，元素）提交一个任务，并等待本次提交的任务全部结束。
+// 每个元素结束时都会以其最终错误（成功为 nil）调用一次 onDone，
+// 包括提交失败、被丢弃与 ctx 结束而未执行的情况。onDone 可能被并发调用。
+func runEach[T any](
+	ctx context.Context,
+	pool *Pool,
+	items iter.Seq2[int, T],
+	fn func(ctx context.Context, i int, item T) error,
+	onDone func(i int, err error),
+) {
+	var wg sync.WaitGroup
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			onDone(i, err)
+			continue
+		}
+
+		wg.Add(1)
+		j := newJob(func(wctx context.Context) error {
+			return fn(wctx, i, item)
+		}, nil)
+		// 以调用方 ctx 作为任务的 ctx（见 SubmitWithContext）：执行的 ctx 随之取消，
+		// 出队时 ctx 已结束的元素直接被跳过，不会被重试，也不计入 Errors
+		if ctx.Done() != nil {
+			j.ctx = ctx
+		}
+		j.after = func(err error) {
+			onDone(i, err)
+			wg.Done()
+		}
+		if err := pool.submit(j); err != nil {
+			onDone(i, err)
+			wg.Done()
+		}
+	}
+	wg.Wait()
+}
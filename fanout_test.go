@@ -0,0 +1,42 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+// TestFanOutCanceledItemsAreSkipped 取消调用方 ctx 后，仍在排队的元素不执行、不重试，也不计入 Errors。
+func TestFanOutCanceledItemsAreSkipped(t *testing.T) {
+	p := newRunningPool(t, 1, WithRetry(3))
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	_, err := FanOut(ctx, p, []int{0, 1, 2, 3}, func(ctx context.Context, in int) (int, error) {
+		calls.Add(1)
+		if in == 0 {
+			// 第一个元素占住唯一的 worker，等其余元素都已排队后再取消
+			for p.QueueLen() < 3 {
+				runtime.Gosched()
+			}
+			cancel()
+			<-ctx.Done()
+		}
+		return in, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("FanOut error = %v, want context.Canceled", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("fn called %d times, want 1", got)
+	}
+	p.Wait()
+	s := p.Stats()
+	if s.Retries != 0 || s.Failed != 0 || len(p.Errors()) != 0 {
+		t.Fatalf("retries=%d failed=%d errors=%v, want canceled items skipped", s.Retries, s.Failed, p.Errors())
+	}
+	if s.Skipped != 3 {
+		t.Fatalf("Skipped = %d, want 3", s.Skipped)
+	}
+}
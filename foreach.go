@@ -0,0 +1,100 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// ForEachOption 是 ForEach 系列辅助函数的可选配置。
+type ForEachOption func(*forEachOptions)
+
+// forEachOptions 保存 ForEach 系列辅助函数的配置项。
+type forEachOptions struct {
+	// stopOnError 表示出现第一个错误后取消剩余工作
+	stopOnError bool
+}
+
+// StopOnError 使 ForEach 在出现第一个错误后取消剩余工作：
+// 尚未开始的元素不再执行，运行中的元素收到 ctx 取消信号，
+// 并且只返回第一个错误。
+func StopOnError() ForEachOption {
+	return func(o *forEachOptions) {
+		o.stopOnError = true
+	}
+}
+
+// ForEach 使用 pool 对 items 中的每个元素执行 fn，并等待全部结束。
+// 默认处理所有元素，并按输入顺序聚合所有错误；
+// 配合 StopOnError 时，行为类似限定并发数的 errgroup：第一个错误胜出，其余工作被取消。
+// ctx 结束（或 StopOnError 触发）后不再提交剩余元素。
+func ForEach[T any](
+	ctx context.Context,
+	pool *Pool,
+	items []T,
+	fn func(ctx context.Context, item T) error,
+	opts ...ForEachOption,
+) error {
+	return forEach(ctx, pool, slices.All(items), fn, opts)
+}
+
+// forEach 是 ForEach 与 ForEachSeq 的共同实现。
+func forEach[T any](
+	ctx context.Context,
+	pool *Pool,
+	items iter.Seq2[int, T],
+	fn func(ctx context.Context, item T) error,
+	opts []ForEachOption,
+) error {
+	var o forEachOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		failures []indexedError
+		firstErr error
+	)
+	runEach(ctx, pool, until(ctx, items), func(ctx context.Context, _ int, item T) error {
+		return fn(ctx, item)
+	}, func(i int, err error) {
+		if err == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if o.stopOnError {
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+			return
+		}
+		failures = append(failures, indexedError{index: i, err: err})
+	})
+
+	if o.stopOnError {
+		return firstErr
+	}
+	// 按输入顺序聚合错误，保证结果与完成顺序无关
+	slices.SortFunc(failures, func(a, b indexedError) int {
+		return a.index - b.index
+	})
+	errs := make([]error, len(failures))
+	for i, f := range failures {
+		errs[i] = f.err
+	}
+	return errors.Join(errs...)
+}
+
+// indexedError 记录某个下标元素的错误，用于按输入顺序聚合。
+type indexedError struct {
+	index int
+	err   error
+}
@@ -1,6 +1,10 @@
 package gopoolx
 
-import "context"
+import (
+	"context"
+	"sync"
+	"time"
+)
 
 // Future 表示一个异步计算结果的占位符。
 // 典型用法为：提交带返回值的任务，得到 *Future[T]，随后在需要时调用 Get 获取结果。
@@ -11,6 +15,17 @@ type Future[T any] struct {
 	err error
 	// done 在任务完成（无论成功或失败）时关闭，用于通知等待方
 	done chan struct{}
+
+	// handle 是底层任务的句柄，用于 Cancel；不对应池中任务时为 nil
+	handle *TaskHandle
+	// pool 是产生该 Future 的池，Then 等组合操作会将后续任务调度到同一个池；可为 nil
+	pool *Pool
+	// lineage 是产生该 Future 的任务的可继承属性，供 Then 传给后续任务；不来自池中任务时为 nil
+	lineage *lineage
+	// mu 保护 callbacks
+	mu sync.Mutex
+	// callbacks 是完成时需要执行的回调
+	callbacks []func()
 }
 
 // newFuture 创建一个尚未完成的 Future。
@@ -22,15 +37,55 @@ func newFuture[T any]() *Future[T] {
 
 // complete 在任务结束时由生产者调用，用于设置结果并通知所有等待方。
 func (f *Future[T]) complete(res T, err error) {
+	f.tryComplete(res, err)
+}
+
+// tryComplete 设置结果并通知所有等待方；Future 已完成时不做任何事并返回 false。
+func (f *Future[T]) tryComplete(res T, err error) bool {
+	f.mu.Lock()
+	select {
+	case <-f.done:
+		f.mu.Unlock()
+		return false
+	default:
+	}
 	f.result = res
 	f.err = err
 	close(f.done)
+	callbacks := f.callbacks
+	f.callbacks = nil
+	f.mu.Unlock()
+
+	for _, cb := range callbacks {
+		cb()
+	}
+	return true
+}
+
+// onComplete 注册一个在 Future 完成时执行的回调；若已完成则立即在当前 goroutine 执行。
+func (f *Future[T]) onComplete(cb func()) {
+	f.mu.Lock()
+	select {
+	case <-f.done:
+		f.mu.Unlock()
+		cb()
+	default:
+		f.callbacks = append(f.callbacks, cb)
+		f.mu.Unlock()
+	}
 }
 
 // Get 阻塞等待任务完成或上下文结束。
 //   - 若 ctx 先结束，则返回零值和 ctx.Err()
 //   - 若任务先完成，则返回任务的 result 与 err
 func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	// 已完成时优先返回结果，避免与同时结束的 ctx 随机竞争
+	select {
+	case <-f.done:
+		return f.result, f.err
+	default:
+	}
+
 	select {
 	case <-ctx.Done():
 		var zero T
@@ -39,3 +94,75 @@ func (f *Future[T]) Get(ctx context.Context) (T, error) {
 		return f.result, f.err
 	}
 }
+
+// GetTimeout 最多等待 d 时间获取结果，等价于使用 context.WithTimeout 调用 Get：
+// 超时后返回零值和 context.DeadlineExceeded。
+func (f *Future[T]) GetTimeout(d time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return f.Get(ctx)
+}
+
+// OnComplete 注册一个在 Future 完成时执行的回调，参数为最终结果与错误。
+// 若 Future 已经完成，回调会在当前 goroutine 中立即执行；
+// 否则在完成 Future 的 goroutine（通常是池的 worker）中执行，回调应避免长时间阻塞。
+// 可以注册多个回调，它们按注册顺序执行。
+func (f *Future[T]) OnComplete(fn func(res T, err error)) {
+	f.onComplete(func() {
+		fn(f.result, f.err)
+	})
+}
+
+// Done 返回一个在 Future 完成时关闭的通道，便于在调用方自己的 select 中等待：
+//
+//	select {
+//	case <-f.Done():
+//		v, err := f.Get(ctx) // 已完成，不会阻塞
+//	case <-other:
+//	}
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// IsDone 报告 Future 是否已经完成，不会阻塞。
+func (f *Future[T]) IsDone() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// TryGet 非阻塞地获取结果：
+//   - 若 Future 已完成，返回其 result、err 与 ok = true
+//   - 若尚未完成，返回零值、nil 与 ok = false
+func (f *Future[T]) TryGet() (res T, err error, ok bool) {
+	select {
+	case <-f.done:
+		return f.result, f.err, true
+	default:
+		var zero T
+		return zero, nil, false
+	}
+}
+
+// Cancel 放弃该 Future 对应的任务：
+//   - 任务尚未开始执行：任务从队列中移除（不会再执行），Future 以 ErrCanceled 完成
+//   - 任务正在执行：取消任务收到的 ctx，Future 以任务最终的返回值完成
+//   - 任务已经结束，或 Future 并非由 SubmitWithResult 产生：不做任何事
+//
+// 返回 true 表示取消生效。被移除的任务不计入 pool.Errors()。
+// 注意：被移除的任务在被 worker 取出前仍占用队列中的一个位置。
+func (f *Future[T]) Cancel() bool {
+	if f.handle == nil {
+		return false
+	}
+	return f.handle.tryCancel()
+}
+
+// Handle 返回 Future 对应任务的句柄，用于查询任务状态；
+// Future 并非由 SubmitWithResult 产生时返回 nil。
+func (f *Future[T]) Handle() *TaskHandle {
+	return f.handle
+}
@@ -0,0 +1,68 @@
+package gopoolx
+
+import (
+	"context"
+	"slices"
+)
+
+// Governor 是跨池的全局并发上限：共享同一个 Governor（见 WithGovernor）的所有池同时执行的任务总数
+// 不超过 max。与 Child 不同，各个池互不隶属、可以分别由不同的模块创建与管理，例如十个各有 50 个
+// worker 的池共享一个上限为 100 的 Governor，整体对数据库的并发始终不超过 100。
+//
+// worker 取到任务后先占用 Governor 的名额再执行，等待期间任务仍处于排队状态，ctx 结束时会被跳过；
+// 名额在任务结束（含全部重试）后归还。Governor 可以被任意多个池并发使用。
+type Governor struct {
+	slots chan struct{}
+}
+
+// NewGovernor 创建一个最多允许 max 个任务同时执行的 Governor，max <= 0 时 panic。
+func NewGovernor(max int) *Governor {
+	if max <= 0 {
+		panic("gopoolx: NewGovernor requires a positive limit")
+	}
+	return &Governor{slots: make(chan struct{}, max)}
+}
+
+// Limit 返回同时执行的任务数上限。
+func (g *Governor) Limit() int {
+	return cap(g.slots)
+}
+
+// Running 返回当前占用名额（正在执行）的任务数。
+func (g *Governor) Running() int {
+	return len(g.slots)
+}
+
+// WithGovernor 让池的每次执行都占用 g 的一个名额，见 Governor。池的子池（见 Child）不会继承它，
+// 需要时为子池同样指定；同一个 Governor 出现在父池与子池上时，一次执行只占用一个名额。
+// 在任务中以 ReentrantCallerRuns 嵌套执行的任务同样需要名额，名额耗尽时会一直等待，
+// 因此共享 Governor 的池应避免这种用法。g 为 nil 表示不启用。
+func WithGovernor(g *Governor) Option {
+	return func(o *Options) {
+		o.governor = g
+	}
+}
+
+// acquireGovernors 在占用父链上各级池的名额之后，依次占用各级池的 Governor 的名额，
+// 已占用的 Governor 不再重复占用。Governor 总是最后占用，避免与父池名额交叉等待而死锁。
+func (p *Pool) acquireGovernors(ctx context.Context, set *shareSet) error {
+	for a := p; a != nil; a = a.parent {
+		g := a.opts.governor
+		if g == nil || set.holds(g.slots) {
+			continue
+		}
+		select {
+		case g.slots <- struct{}{}:
+			set.add(g.slots)
+		case <-ctx.Done():
+			set.release()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// holds 判断是否已占用 s 的名额。
+func (set *shareSet) holds(s chan struct{}) bool {
+	return slices.Contains(set.held[:min(set.n, len(set.held))], s) || slices.Contains(set.more, s)
+}
@@ -0,0 +1,229 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrDependencyFailed 表示任务因前置依赖失败而未被执行。
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// NodeOption 是向 Graph 添加任务时的可选配置。
+type NodeOption func(*graphNode)
+
+// After 声明当前任务依赖的前置任务，只有它们全部成功后当前任务才会执行。
+func After(names ...string) NodeOption {
+	return func(n *graphNode) {
+		n.deps = append(n.deps, names...)
+	}
+}
+
+// graphNode 是 Graph 中的一个任务节点。
+type graphNode struct {
+	name string
+	task Task
+	// deps 是前置依赖的任务名
+	deps []string
+	// dependents 是依赖当前任务的后继节点，在 Run 时构建
+	dependents []*graphNode
+}
+
+// Graph 是一个按依赖关系（DAG）组织任务的构建器。
+// 典型用法：
+//
+//	g := gopoolx.NewGraph()
+//	g.Add("a", taskA)
+//	g.Add("b", taskB, gopoolx.After("a"))
+//	g.Add("c", taskC, gopoolx.After("a"))
+//	g.Add("d", taskD, gopoolx.After("b", "c"))
+//	err := g.Run(ctx, pool)
+//
+// Run 会在依赖满足后尽可能并行地执行任务；某个任务失败时，
+// 所有直接或间接依赖它的任务都不会执行，并以 ErrDependencyFailed 失败。
+type Graph struct {
+	nodes map[string]*graphNode
+	// order 记录任务的添加顺序，保证提交与错误报告的顺序稳定
+	order []*graphNode
+	// errs 记录构建过程中的错误（如重复任务名），在 Run 时返回
+	errs []error
+}
+
+// NewGraph 创建一个空的任务依赖图。
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]*graphNode),
+	}
+}
+
+// Add 向图中添加一个名为 name 的任务，可通过 After 声明依赖。
+// 重复的任务名会在 Run 时以错误形式返回。返回 g 本身以便链式调用。
+func (g *Graph) Add(name string, task Task, opts ...NodeOption) *Graph {
+	if _, ok := g.nodes[name]; ok {
+		g.errs = append(g.errs, fmt.Errorf("graph: duplicate task %q", name))
+		return g
+	}
+	n := &graphNode{name: name, task: task}
+	for _, opt := range opts {
+		opt(n)
+	}
+	g.nodes[name] = n
+	g.order = append(g.order, n)
+	return g
+}
+
+// validate 检查图的合法性：构建错误、缺失的依赖与环，并构建后继关系。
+func (g *Graph) validate() error {
+	if len(g.errs) > 0 {
+		return errors.Join(g.errs...)
+	}
+
+	for _, n := range g.order {
+		n.dependents = nil
+	}
+	for _, n := range g.order {
+		for _, dep := range n.deps {
+			d, ok := g.nodes[dep]
+			if !ok {
+				return fmt.Errorf("graph: task %q depends on unknown task %q", n.name, dep)
+			}
+			d.dependents = append(d.dependents, n)
+		}
+	}
+
+	// 使用 Kahn 算法检测环
+	indegree := make(map[*graphNode]int, len(g.order))
+	var queue []*graphNode
+	for _, n := range g.order {
+		indegree[n] = len(n.deps)
+		if len(n.deps) == 0 {
+			queue = append(queue, n)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, d := range n.dependents {
+			indegree[d]--
+			if indegree[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+	if visited != len(g.order) {
+		return errors.New("graph: dependency cycle detected")
+	}
+	return nil
+}
+
+// graphRun 保存一次 Run 的执行状态。
+type graphRun struct {
+	ctx  context.Context
+	pool *Pool
+
+	mu sync.Mutex
+	// remaining 是每个节点尚未完成的前置依赖数
+	remaining map[*graphNode]int
+	// errs 记录每个节点的最终错误
+	errs map[*graphNode]error
+	// wg 等待所有节点结束（执行完成或被跳过）
+	wg sync.WaitGroup
+}
+
+// Run 在 pool 上按依赖关系执行图中的所有任务，并阻塞直到全部结束。
+// 图不合法（重复任务名、未知依赖、存在环）时不会执行任何任务，直接返回错误。
+// 返回值聚合了所有失败任务的错误（包括因依赖失败而跳过的任务），
+// 每个错误都带有任务名，可通过 errors.Is 判断 ErrDependencyFailed。
+// ctx 结束后，尚未开始的任务以 ctx.Err() 失败，运行中的任务收到取消信号。
+func (g *Graph) Run(ctx context.Context, pool *Pool) error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+
+	r := &graphRun{
+		ctx:       ctx,
+		pool:      pool,
+		remaining: make(map[*graphNode]int, len(g.order)),
+		errs:      make(map[*graphNode]error),
+	}
+	var ready []*graphNode
+	for _, n := range g.order {
+		r.remaining[n] = len(n.deps)
+		if len(n.deps) == 0 {
+			ready = append(ready, n)
+		}
+	}
+
+	r.wg.Add(len(g.order))
+	for _, n := range ready {
+		r.start(n)
+	}
+	r.wg.Wait()
+
+	var errs []error
+	for _, n := range g.order {
+		if err := r.errs[n]; err != nil {
+			errs = append(errs, fmt.Errorf("task %q: %w", n.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// start 将节点提交到池中执行；提交失败时直接按失败处理。
+func (r *graphRun) start(n *graphNode) {
+	if err := r.ctx.Err(); err != nil {
+		r.finish(n, err)
+		return
+	}
+
+	j := newJob(func(wctx context.Context) error {
+		ctx, cancel := mergeContext(wctx, r.ctx)
+		defer cancel()
+		return n.task(ctx)
+	}, nil)
+	j.after = func(err error) {
+		r.finish(n, err)
+	}
+	if err := r.pool.submit(j); err != nil {
+		r.finish(n, err)
+	}
+}
+
+// finish 记录节点的最终结果，并推进后继节点：
+// 成功时启动依赖已全部满足的后继，失败时将所有后继标记为依赖失败。
+func (r *graphRun) finish(n *graphNode, err error) {
+	var ready []*graphNode
+
+	r.mu.Lock()
+	if err != nil {
+		r.errs[n] = err
+	}
+	for _, d := range n.dependents {
+		if err != nil && r.errs[d] == nil {
+			r.errs[d] = fmt.Errorf("%w: %q", ErrDependencyFailed, n.name)
+		}
+		r.remaining[d]--
+		if r.remaining[d] == 0 {
+			ready = append(ready, d)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, d := range ready {
+		r.mu.Lock()
+		failed := r.errs[d]
+		r.mu.Unlock()
+		if failed != nil {
+			// 依赖失败：不执行该任务，直接向后继传播失败
+			r.finish(d, failed)
+			continue
+		}
+		// finish 通常在 worker 中被调用，异步提交后继任务，
+		// 避免队列已满时 worker 阻塞在提交上造成死锁
+		go r.start(d)
+	}
+	r.wg.Done()
+}
@@ -0,0 +1,92 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+)
+
+// Group 提供与 golang.org/x/sync/errgroup 相同的使用方式，
+// 但函数在池的 worker 中执行，并发度受池的 worker 数量约束。
+// 典型用法：
+//
+//	g := pool.Group(ctx)
+//	for _, url := range urls {
+//		g.Go(func() error {
+//			return fetch(g.Context(), url)
+//		})
+//	}
+//	err := g.Wait()
+//
+// 与 errgroup 的差异：函数返回错误时会按池的配置重试，错误同样计入 pool.Errors()。
+//
+// 同一个池上可以同时存在任意多个 Group：它们共享池的 worker，
+// 但各自的 Wait 只等待本组提交的函数，Errors 也只包含本组的错误，
+// 因此一个服务级的池可以同时服务多个请求级的批次。
+type Group struct {
+	pool   *Pool
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	// err 是第一个返回的错误
+	err error
+	// errs 收集本组所有函数的最终错误
+	errs ErrorCollector
+}
+
+// Group 创建一个在当前池上执行函数的任务组。
+// 组内第一个函数返回错误时，Context 返回的上下文会被取消。
+func (p *Pool) Group(ctx context.Context) *Group {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{
+		pool:   p,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context 返回组的上下文：组内出现第一个错误、Wait 返回或父 ctx 结束时被取消。
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go 将 f 提交到池中执行。提交失败（队列满、任务被丢弃等）同样视为 f 返回了该错误。
+func (g *Group) Go(f func() error) {
+	g.wg.Add(1)
+
+	j := newJob(func(context.Context) error {
+		return f()
+	}, nil)
+	j.after = func(err error) {
+		g.finish(err)
+	}
+	if err := g.pool.submit(j); err != nil {
+		g.finish(err)
+	}
+}
+
+// Wait 阻塞直到组内所有函数结束，返回第一个非 nil 的错误（如果有）。
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(g.err)
+	return g.err
+}
+
+// Errors 返回本组目前为止所有失败函数的错误副本（按完成顺序）。
+// 在 Wait 返回后调用可以得到本组完整的错误列表。
+func (g *Group) Errors() []error {
+	return g.errs.Errors()
+}
+
+// finish 记录一个函数的结果，第一个错误会取消组的上下文。
+func (g *Group) finish(err error) {
+	if err != nil {
+		g.errs.Add(err)
+		g.errOnce.Do(func() {
+			g.err = err
+			g.cancel(err)
+		})
+	}
+	g.wg.Done()
+}
@@ -0,0 +1,301 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCanceled 表示任务在开始执行前被取消。
+var ErrCanceled = errors.New("task canceled")
+
+// TaskStatus 表示任务的生命周期状态。
+type TaskStatus int
+
+const (
+	// TaskQueued 任务已提交，尚未开始执行
+	TaskQueued TaskStatus = iota
+	// TaskRunning 任务正在执行（包括重试期间）
+	TaskRunning
+	// TaskDone 任务执行成功
+	TaskDone
+	// TaskFailed 任务最终执行失败，或提交失败（如队列已满）
+	TaskFailed
+	// TaskDiscarded 任务按丢弃策略被丢弃，不会执行
+	TaskDiscarded
+	// TaskCanceled 任务在开始执行前被取消
+	TaskCanceled
+)
+
+// String 返回状态的可读名称。
+func (s TaskStatus) String() string {
+	switch s {
+	case TaskQueued:
+		return "queued"
+	case TaskRunning:
+		return "running"
+	case TaskDone:
+		return "done"
+	case TaskFailed:
+		return "failed"
+	case TaskDiscarded:
+		return "discarded"
+	case TaskCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// TaskHandle 是已提交任务的句柄，可用于查询任务的执行进度，
+// 便于外部系统展示每个任务的状态。所有方法都可以并发调用。
+type TaskHandle struct {
+	id   uint64
+	pool *Pool
+	j    *job
+	// submittedAt 是任务提交的时间
+	submittedAt time.Time
+
+	mu     sync.Mutex
+	status TaskStatus
+	// startedAt / finishedAt 是任务开始与结束执行的时间，未发生时为零值
+	startedAt  time.Time
+	finishedAt time.Time
+	// err 是任务的最终错误
+	err error
+	// queueWait / attempts 是任务的排队时长与执行次数，在任务执行结束时记录
+	queueWait time.Duration
+	attempts  int
+	// cancel 以 ErrCanceled 为原因取消执行中任务的 ctx，仅在 TaskRunning 状态下有效
+	cancel context.CancelCauseFunc
+}
+
+// newTaskHandle 为 j 创建句柄并挂载到任务上。句柄在任务登记计数后才加入注册表（见 Pool.register），
+// 在此之前无法通过 Pool.Cancel 找到，取消不会释放一个从未登记的计数。
+func newTaskHandle(pool *Pool, j *job) *TaskHandle {
+	h := &TaskHandle{
+		id:          pool.nextID.Add(1),
+		pool:        pool,
+		j:           j,
+		submittedAt: pool.opts.clock.Now(),
+	}
+	j.handle = h
+	return h
+}
+
+// register 将已登记计数的任务 j 的句柄加入注册表，使其可以被 Tasks 列出、被 Pool.Cancel 取消。
+func (p *Pool) register(j *job) {
+	if j.handle != nil {
+		p.registry.add(j.handle)
+	}
+}
+
+// ID 返回任务在池内唯一的编号。
+func (h *TaskHandle) ID() uint64 {
+	return h.id
+}
+
+// Status 返回任务当前的状态。
+func (h *TaskHandle) Status() TaskStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// SubmittedAt 返回任务的提交时间。
+func (h *TaskHandle) SubmittedAt() time.Time {
+	return h.submittedAt
+}
+
+// StartedAt 返回任务开始执行的时间；尚未开始时返回零值。
+func (h *TaskHandle) StartedAt() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.startedAt
+}
+
+// FinishedAt 返回任务结束的时间；尚未结束时返回零值。
+func (h *TaskHandle) FinishedAt() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.finishedAt
+}
+
+// Err 返回任务的最终错误；任务成功或尚未结束时返回 nil。
+func (h *TaskHandle) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// start 由 worker 在执行前调用，返回 false 表示任务已被取消、应直接跳过。
+func (h *TaskHandle) start(cancel context.CancelCauseFunc) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.status != TaskQueued {
+		return false
+	}
+	h.status = TaskRunning
+	// 重新入队重试的任务保留第一次开始执行的时间
+	if h.startedAt.IsZero() {
+		h.startedAt = h.pool.opts.clock.Now()
+	}
+	h.cancel = cancel
+	return true
+}
+
+// requeue 在任务回到延迟队列等待重试时将其置回 TaskQueued，等待期间可以按排队任务取消，见 WithRetryRequeue。
+func (h *TaskHandle) requeue() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.status == TaskRunning {
+		h.status = TaskQueued
+		h.cancel = nil
+	}
+}
+
+// finish 记录任务的最终结果：err 为 nil 时为 TaskDone，否则为 TaskFailed，
+// 并返回结束时的快照。
+func (h *TaskHandle) finish(err error, queueWait time.Duration, attempts int) TaskInfo {
+	status := TaskDone
+	if err != nil {
+		status = TaskFailed
+	}
+	h.mu.Lock()
+	h.queueWait, h.attempts = queueWait, attempts
+	h.settleLocked(status, err)
+	info := h.infoLocked()
+	h.mu.Unlock()
+
+	h.pool.registry.finish(info)
+	return info
+}
+
+// settleLocked 是 settle 的加锁内实现，调用方需持有 h.mu。
+func (h *TaskHandle) settleLocked(status TaskStatus, err error) {
+	h.status = status
+	h.finishedAt = h.pool.opts.clock.Now()
+	h.err = err
+	h.cancel = nil
+}
+
+// settleIfQueued 仅当任务仍处于 TaskQueued 状态时将其置为结束状态 status，
+// 返回是否发生了状态转换（false 表示任务已被取消或已开始）。
+func (h *TaskHandle) settleIfQueued(status TaskStatus, err error) bool {
+	h.mu.Lock()
+	if h.status != TaskQueued {
+		h.mu.Unlock()
+		return false
+	}
+	h.settleLocked(status, err)
+	info := h.infoLocked()
+	h.mu.Unlock()
+
+	h.pool.registry.finish(info)
+	return true
+}
+
+// abort 在执行中的任务被中断（worker 意外退出）时将其置为 TaskFailed；任务已结束时不做任何事。
+func (h *TaskHandle) abort(err error) {
+	h.mu.Lock()
+	if h.status != TaskRunning {
+		h.mu.Unlock()
+		return
+	}
+	h.settleLocked(TaskFailed, err)
+	info := h.infoLocked()
+	h.mu.Unlock()
+
+	h.pool.registry.finish(info)
+}
+
+// tryCancel 取消任务：
+//   - 尚未开始：标记为已取消（worker 出队后会跳过），以 ErrCanceled 结束任务并释放计数
+//   - 正在执行：取消任务的 ctx，由任务自行响应
+//   - 已结束：不做任何事
+//
+// 返回 true 表示取消生效（移除了排队任务或发出了取消信号）。
+func (h *TaskHandle) tryCancel() bool {
+	h.mu.Lock()
+	switch h.status {
+	case TaskQueued:
+		h.settleLocked(TaskCanceled, ErrCanceled)
+		info := h.infoLocked()
+		h.mu.Unlock()
+
+		h.pool.registry.finish(info)
+		h.j.acknowledge(ErrCanceled)
+		if h.j.after != nil {
+			h.j.after(ErrCanceled)
+		}
+		if h.j.coalesce != nil {
+			h.pool.settleCoalesced(h.j, ErrCanceled)
+		}
+		h.pool.done(h.j)
+		return true
+	case TaskRunning:
+		h.cancel(ErrCanceled)
+		h.mu.Unlock()
+		return true
+	default:
+		h.mu.Unlock()
+		return false
+	}
+}
+
+// Info 返回任务当前状态的快照。
+func (h *TaskHandle) Info() TaskInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.infoLocked()
+}
+
+// infoLocked 生成任务快照，调用方需持有 h.mu。
+func (h *TaskHandle) infoLocked() TaskInfo {
+	return TaskInfo{
+		Pool:        h.pool.opts.name,
+		ID:          h.id,
+		Name:        h.j.name,
+		Lane:        h.j.lane,
+		Tags:        h.j.tags,
+		Status:      h.status,
+		SubmittedAt: h.submittedAt,
+		StartedAt:   h.startedAt,
+		FinishedAt:  h.finishedAt,
+		Err:         h.err,
+		QueueWait:   h.queueWait,
+		Attempts:    h.attempts,
+	}
+}
+
+// Cancel 取消任务：尚未开始的任务从队列中移除并标记为 TaskCanceled
+// （不会执行，不计入 Errors，计数被立即释放）；正在执行的任务收到 ctx 取消信号。
+// 任务已结束时不做任何事。返回 true 表示取消生效。
+// 正在执行的任务可以通过 context.Cause(ctx) 得到 ErrCanceled，以区分于池或提交方的取消。
+func (h *TaskHandle) Cancel() bool {
+	return h.tryCancel()
+}
+
+// Cancel 按编号取消一个仍未结束的任务，语义与 TaskHandle.Cancel 相同。
+// 只有带句柄的任务（SubmitWithHandle、SubmitWithResult 等）可以按编号取消；
+// 编号不存在或任务已结束时返回 false。
+func (p *Pool) Cancel(id uint64) bool {
+	h := p.registry.get(id)
+	if h == nil {
+		return false
+	}
+	return h.Cancel()
+}
+
+// SubmitWithHandle 与 Submit 相同，但额外返回任务的句柄，用于跟踪任务状态。
+// 任务被丢弃时返回的句柄状态为 TaskDiscarded、错误为 nil；
+// 提交失败时句柄状态为 TaskFailed，并同时返回错误。
+func (p *Pool) SubmitWithHandle(task Task, opts ...SubmitOption) (*TaskHandle, error) {
+	j := newJob(task, opts)
+	h := newTaskHandle(p, j)
+	if err := p.submit(j); err != nil && err != ErrDiscarded {
+		return h, err
+	}
+	return h, nil
+}
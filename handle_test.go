@@ -0,0 +1,33 @@
+package gopoolx
+
+import (
+	"context"
+	"testing"
+)
+
+// TestCancelBeforeAdmission 在任务登记计数之前（准入过滤器中）按编号取消它：
+// 取消不应生效，也不应释放任务从未登记的计数。
+func TestCancelBeforeAdmission(t *testing.T) {
+	var p *Pool
+	canceled := false
+	p = newRunningPool(t, 1, WithTaskTracking(0), WithMaxPending(1), WithAdmissionFilter(func(info TaskInfo) error {
+		canceled = canceled || p.Cancel(info.ID)
+		return nil
+	}))
+
+	ran := false
+	h, err := p.SubmitWithHandle(func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Wait()
+	if canceled {
+		t.Fatal("Cancel succeeded for a task that had not been admitted")
+	}
+	if !ran || h.Status() != TaskDone {
+		t.Fatalf("ran=%v status=%v, want the task to run to completion", ran, h.Status())
+	}
+}
@@ -0,0 +1,100 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTaskTimeout 表示任务执行超过了池设定的时长。超时时任务的 ctx 以它为原因被取消
+// （可通过 context.Cause(ctx) 得到），ErrTaskAbandoned 也包装了它，
+// 因此 errors.Is(err, ErrTaskTimeout) 可以统一判断超时类失败。
+var ErrTaskTimeout = errors.New("task timed out")
+
+// ErrTaskAbandoned 表示任务执行超过 WithHardTimeout 设定的时长，且在 ctx 被取消后仍未返回，
+// worker 已放弃等待。任务所在的 goroutine 仍在运行，其后返回的结果会被丢弃。
+// 它包装了 ErrTaskTimeout。
+var ErrTaskAbandoned = fmt.Errorf("task abandoned after hard timeout: %w", ErrTaskTimeout)
+
+// WithHardTimeout 为每次执行设置硬超时 d：执行超过 d 时先取消任务的 ctx，
+// 若任务仍未返回（例如阻塞在 cgo 或系统调用中、或根本不检查 ctx），worker 不再等待，
+// 以 ErrTaskAbandoned 结束该任务（不再重试）并继续处理后续任务，使池的并发能力不被卡死。
+//
+// 启用后每次执行都在独立的 goroutine 中进行，worker 只负责等待其结果；
+// 被放弃的 goroutine 无法被强制终止，会以 onAbandon（可为 nil）报告任务名，
+// 并计入 Stats().Abandoned 与 Stats().Leaked，直到其最终返回。d <= 0 表示不启用。
+func WithHardTimeout(d time.Duration, onAbandon func(name string)) Option {
+	return func(o *Options) {
+		o.hardTimeout = d
+		o.onAbandon = onAbandon
+	}
+}
+
+// runAttempt 执行任务的一次尝试。启用 WithHardTimeout 时在独立 goroutine 中执行，
+// 超时后放弃等待并返回 ErrTaskAbandoned。
+func (p *Pool) runAttempt(ctx context.Context, j *job) error {
+	task := p.wrap(j.task)
+	if p.opts.hardTimeout <= 0 {
+		return task.run(ctx)
+	}
+
+	actx, cancel := context.WithCancelCause(ctx)
+	buf, lent := workerBufferOf(ctx), (*workerBuffer)(nil)
+	if buf != nil {
+		actx, lent = buf.lend(actx)
+	}
+	result := make(chan error, 1)
+	go func() {
+		// panic 不能跨 goroutine 传播，在此转换为 error 交给 worker；
+		// 内置恢复被替换时由替换的中间件负责，被关闭时任由进程崩溃
+		defer func() {
+			if p.opts.customRecovery {
+				return
+			}
+			if r := recover(); r != nil {
+				result <- panicError(r)
+			}
+		}()
+		result <- task.run(actx)
+	}()
+
+	timer := p.opts.clock.NewTimer(p.opts.hardTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		cancel(nil)
+		if buf != nil {
+			buf.giveBack(lent)
+		}
+		return err
+	case <-timer.C():
+	}
+
+	// 超时：取消 ctx 后任务已无机会正常结束，放弃等待
+	cancel(ErrTaskTimeout)
+	select {
+	case err := <-result:
+		// 任务恰好在取消的同时返回
+		if buf != nil {
+			buf.giveBack(lent)
+		}
+		return err
+	default:
+	}
+	if buf != nil {
+		buf.forfeit()
+	}
+	// 仍在运行的 goroutine 持有任务的执行体，任务对象不能再被回收复用
+	j.pooled = false
+	p.stats.abandoned.Add(1)
+	p.stats.leaked.Add(1)
+	go func() {
+		<-result
+		p.stats.leaked.Add(-1)
+	}()
+	if p.opts.onAbandon != nil {
+		p.opts.onAbandon(j.name)
+	}
+	return ErrTaskAbandoned
+}
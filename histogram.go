@@ -0,0 +1,115 @@
+package gopoolx
+
+import (
+	"math"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// defaultLatencyBuckets 是 WithLatencyHistogram 未指定桶边界时使用的默认值。
+var defaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// WithLatencyHistogram 启用任务耗时直方图：记录每个任务的排队等待时间与执行时间
+// （含全部重试），通过 Stats().QueueWait / Stats().Exec 读取。
+// buckets 是升序的桶上界，为空时使用 1ms ~ 10s 的默认划分。
+// 排队等待时间的高分位数往往在队列真正溢出之前就开始上涨，适合作为扩容或限流的信号。
+func WithLatencyHistogram(buckets ...time.Duration) Option {
+	return func(o *Options) {
+		if len(buckets) == 0 {
+			buckets = defaultLatencyBuckets
+		}
+		o.latencyBuckets = slices.Clone(buckets)
+		slices.Sort(o.latencyBuckets)
+	}
+}
+
+// Histogram 是耗时直方图的快照。
+type Histogram struct {
+	// Buckets 是升序的桶上界
+	Buckets []time.Duration
+	// Counts[i] 是耗时不超过 Buckets[i]（且超过前一个上界）的样本数；
+	// 最后一个元素 Counts[len(Buckets)] 统计超过所有上界的样本
+	Counts []uint64
+	// Count 是样本总数
+	Count uint64
+	// Sum 是所有样本耗时之和
+	Sum time.Duration
+}
+
+// Mean 返回样本的平均耗时，没有样本时返回 0。
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile 返回分位数 q（0 ~ 1）的估计值，即累计样本数首次达到 q 比例的桶上界。
+// 落入溢出桶时返回最大的桶上界；没有样本时返回 0。
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 || len(h.Buckets) == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(q * float64(h.Count)))
+	target = max(target, 1)
+	var seen uint64
+	for i, c := range h.Counts[:len(h.Buckets)] {
+		seen += c
+		if seen >= target {
+			return h.Buckets[i]
+		}
+	}
+	return h.Buckets[len(h.Buckets)-1]
+}
+
+// histogram 是并发安全的耗时直方图，各桶使用原子计数。
+type histogram struct {
+	buckets []time.Duration
+	counts  []atomic.Uint64
+	count   atomic.Uint64
+	sum     atomic.Int64
+}
+
+// newHistogram 按桶上界创建直方图。
+func newHistogram(buckets []time.Duration) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]atomic.Uint64, len(buckets)+1),
+	}
+}
+
+// observe 记录一个样本。
+func (h *histogram) observe(d time.Duration) {
+	i, _ := slices.BinarySearch(h.buckets, d)
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+// snapshot 返回直方图的快照；h 为 nil（未启用）时返回零值。
+func (h *histogram) snapshot() Histogram {
+	if h == nil {
+		return Histogram{}
+	}
+	counts := make([]uint64, len(h.counts))
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+	}
+	return Histogram{
+		Buckets: slices.Clone(h.buckets),
+		Counts:  counts,
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+	}
+}
@@ -0,0 +1,136 @@
+package gopoolx
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// HookPanicError 是用户回调中的 panic 被恢复后得到的错误，计入 Errors。
+// 指标 sink、队列水位回调、任务结束回调、错误上报方、资源采样与空闲回调以及 onStale、onAbandon 回调都会被这样保护，
+// 它们既可能在 worker 中、也可能在提交方中调用，一个有缺陷的回调不会因此拖垮提交方或 worker。
+type HookPanicError struct {
+	// Hook 是发生 panic 的回调名，如 "MetricsSink.SetGauge"
+	Hook string
+	// Value 是 recover 得到的原始值
+	Value any
+	// Stack 是 panic 发生时的 goroutine 调用栈
+	Stack []byte
+}
+
+// Error 实现 error，返回 "hook <name> panic: <value>"。
+func (e *HookPanicError) Error() string {
+	return fmt.Sprintf("hook %s panic: %v", e.Hook, e.Value)
+}
+
+// Unwrap 在 panic 的值本身是 error 时返回它。
+func (e *HookPanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// recoverHook 恢复回调 hook 中的 panic 并将其加入错误收集器，需直接在 defer 中调用。
+func (p *Pool) recoverHook(hook string) {
+	if r := recover(); r != nil {
+		p.errs.Add(&HookPanicError{Hook: hook, Value: r, Stack: debug.Stack()})
+	}
+}
+
+// guardHooks 将配置中的用户回调替换为带 panic 恢复的版本，未配置的回调保持为 nil。
+func (p *Pool) guardHooks() {
+	o := p.opts
+	if o.metrics != nil {
+		o.metrics = guardedSink{sink: o.metrics, p: p}
+	}
+	if o.reporter != nil {
+		o.reporter = guardedReporter{r: o.reporter, p: p}
+	}
+	if fn := o.onTaskComplete; fn != nil {
+		o.onTaskComplete = func(info TaskInfo) {
+			defer p.recoverHook("OnTaskComplete")
+			fn(info)
+		}
+	}
+	if fn := o.onStale; fn != nil {
+		o.onStale = func(name string, waited time.Duration) {
+			defer p.recoverHook("OnStale")
+			fn(name, waited)
+		}
+	}
+	if fn := o.onAbandon; fn != nil {
+		o.onAbandon = func(name string) {
+			defer p.recoverHook("OnAbandon")
+			fn(name)
+		}
+	}
+	if s := o.sampling; s != nil && s.cfg.OnHeavy != nil {
+		fn := s.cfg.OnHeavy
+		s.cfg.OnHeavy = func(u ResourceUsage) {
+			defer p.recoverHook("ResourceSampling.OnHeavy")
+			fn(u)
+		}
+	}
+	if fn := o.admissionFilter; fn != nil {
+		// 过滤器 panic 时拒绝任务，而不是放行
+		o.admissionFilter = func(info TaskInfo) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					herr := &HookPanicError{Hook: "AdmissionFilter", Value: r, Stack: debug.Stack()}
+					p.errs.Add(herr)
+					err = herr
+				}
+			}()
+			return fn(info)
+		}
+	}
+	o.onIdle = p.guardFunc("OnIdle", o.onIdle)
+	if w := o.watermarks; w != nil {
+		w.onHigh = p.guardFunc("QueueWatermarks.onHigh", w.onHigh)
+		w.onLow = p.guardFunc("QueueWatermarks.onLow", w.onLow)
+	}
+}
+
+// guardFunc 返回带 panic 恢复的 fn，fn 为 nil 时返回 nil。
+func (p *Pool) guardFunc(hook string, fn func()) func() {
+	if fn == nil {
+		return nil
+	}
+	return func() {
+		defer p.recoverHook(hook)
+		fn()
+	}
+}
+
+// guardedSink 是带 panic 恢复的 MetricsSink。
+type guardedSink struct {
+	sink MetricsSink
+	p    *Pool
+}
+
+func (g guardedSink) IncCounter(name string, delta uint64) {
+	defer g.p.recoverHook("MetricsSink.IncCounter")
+	g.sink.IncCounter(name, delta)
+}
+
+func (g guardedSink) ObserveDuration(name string, d time.Duration) {
+	defer g.p.recoverHook("MetricsSink.ObserveDuration")
+	g.sink.ObserveDuration(name, d)
+}
+
+func (g guardedSink) SetGauge(name string, value float64) {
+	defer g.p.recoverHook("MetricsSink.SetGauge")
+	g.sink.SetGauge(name, value)
+}
+
+// guardedReporter 是带 panic 恢复的 Reporter。
+type guardedReporter struct {
+	r Reporter
+	p *Pool
+}
+
+func (g guardedReporter) Report(err error, info TaskInfo) {
+	defer g.p.recoverHook("Reporter.Report")
+	g.r.Report(err, info)
+}
@@ -0,0 +1,60 @@
+package gopoolx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// idempotencyKey 是幂等键在 ctx 中的键。
+type idempotencyKey struct{}
+
+// WithIdempotencyKey 为任务指定幂等键 key。池在任务的每次执行（包括所有重试与降级函数）中
+// 都以同一个 key 注入 ctx，任务通过 IdempotencyKey 取出并随写请求发送，下游即可据此对重试造成的
+// 重复写入去重。空字符串表示不指定。
+func WithIdempotencyKey(key string) SubmitOption {
+	return func(o *submitOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithAutoIdempotencyKeys 让池为每个未指定幂等键（见 WithIdempotencyKey）的任务在提交时生成一个
+// 随机的 UUID（版本 4）作为幂等键。生成会在每次提交时额外分配并读取系统随机数，只在需要时开启。
+func WithAutoIdempotencyKeys() Option {
+	return func(o *Options) {
+		o.autoIdempotencyKeys = true
+	}
+}
+
+// IdempotencyKey 返回 ctx 所属任务的幂等键；任务没有幂等键（或 ctx 不是池中任务的 ctx）时 ok 为 false。
+func IdempotencyKey(ctx context.Context) (key string, ok bool) {
+	key, ok = ctx.Value(idempotencyKey{}).(string)
+	return key, ok
+}
+
+// assignIdempotencyKey 在启用 WithAutoIdempotencyKeys 时为尚无幂等键的任务生成一个。
+func (p *Pool) assignIdempotencyKey(j *job) {
+	if p.opts.autoIdempotencyKeys && j.idempotencyKey == "" {
+		j.idempotencyKey = newUUID()
+	}
+}
+
+// newUUID 生成一个随机的版本 4 UUID，形如 "xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx"。
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
@@ -0,0 +1,66 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// keyRecorder 记录每次执行看到的幂等键。
+type keyRecorder struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *keyRecorder) task(ctx context.Context) error {
+	key, _ := IdempotencyKey(ctx)
+	r.mu.Lock()
+	r.keys = append(r.keys, key)
+	r.mu.Unlock()
+	return nil
+}
+
+// check 断言记录了 n 个互不相同的非空幂等键。
+func (r *keyRecorder) check(t *testing.T, n int) {
+	t.Helper()
+	seen := make(map[string]bool)
+	for _, k := range r.keys {
+		if k == "" {
+			t.Fatalf("task ran without an idempotency key: %q", r.keys)
+		}
+		seen[k] = true
+	}
+	if len(r.keys) != n || len(seen) != n {
+		t.Fatalf("got keys %q, want %d distinct keys", r.keys, n)
+	}
+}
+
+func TestAutoIdempotencyKeysSubmitAll(t *testing.T) {
+	for _, atomicBatch := range []bool{false, true} {
+		opts := []Option{WithAutoIdempotencyKeys()}
+		if atomicBatch {
+			opts = append(opts, WithAtomicBatch(), WithQueueFullPolicy(QueueFullReturnError))
+		}
+		p := newRunningPool(t, 2, opts...)
+		var rec keyRecorder
+		if _, err := p.SubmitAll(rec.task, rec.task, rec.task); err != nil {
+			t.Fatalf("atomic=%v: SubmitAll: %v", atomicBatch, err)
+		}
+		p.Wait()
+		rec.check(t, 3)
+	}
+}
+
+func TestAutoIdempotencyKeysDelayed(t *testing.T) {
+	p := newRunningPool(t, 1, WithAutoIdempotencyKeys())
+	var rec keyRecorder
+	if err := p.SubmitAfter(time.Millisecond, rec.task); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SubmitAt(time.Now().Add(time.Millisecond), rec.task); err != nil {
+		t.Fatal(err)
+	}
+	p.Wait()
+	rec.check(t, 2)
+}
@@ -0,0 +1,65 @@
+package gopoolx
+
+import (
+	"context"
+	"time"
+)
+
+// WithOnIdle 设置空闲回调：池连续 d 没有排队与执行中的任务时调用一次 fn，此后有任务执行过、
+// 再次连续空闲 d 时再调用，可用于在持续型负载安静下来时自动刷新下游缓冲，或让进程缩容。
+// 空闲按 d/4 的间隔检查，回调在空闲达到 d 后的 d/4 内触发；尚未到期的延迟任务不算作排队。
+// fn 在独立的 goroutine 中调用，回调期间不会重复触发。d <= 0 或 fn 为 nil 时不生效。
+func WithOnIdle(d time.Duration, fn func()) Option {
+	return func(o *Options) {
+		o.idleAfter = d
+		o.onIdle = fn
+	}
+}
+
+// watchIdle 检测池的空闲状态并触发空闲回调，直到 ctx 结束或池被关闭。
+func (p *Pool) watchIdle(ctx context.Context) {
+	interval := p.opts.idleAfter / 4
+	if interval <= 0 {
+		interval = p.opts.idleAfter
+	}
+	timer := p.opts.clock.NewTimer(interval)
+	defer timer.Stop()
+
+	var (
+		// finished 是上次检查时已结束的任务数，检查之间有任务结束说明这期间并不空闲
+		finished   = p.finishedTasks()
+		quietSince = p.opts.clock.Now()
+		// dirty 表示上次检查时池仍在忙碌，quietSince 需要在首次观察到空闲时重新计时
+		dirty bool
+		fired bool
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.closed:
+			return
+		case <-timer.C():
+			timer.Reset(interval)
+		}
+		now := p.opts.clock.Now()
+		if p.queued.Load() > 0 || p.running.Load() > 0 {
+			dirty, fired = true, false
+			continue
+		}
+		if n := p.finishedTasks(); n != finished || dirty {
+			finished, quietSince = n, now
+			dirty, fired = false, false
+			continue
+		}
+		if !fired && now.Sub(quietSince) >= p.opts.idleAfter {
+			fired = true
+			p.opts.onIdle()
+		}
+	}
+}
+
+// finishedTasks 返回已结束（执行完成或出队后被跳过）的任务总数。
+func (p *Pool) finishedTasks() uint64 {
+	return p.stats.succeeded.Load() + p.stats.failed.Load() + p.stats.skipped.Load() + p.stats.stale.Load()
+}
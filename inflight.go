@@ -0,0 +1,75 @@
+package gopoolx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// inflightClosed 是 inflight.state 中表示池已关闭的标志位，其余低位为在途计数。
+const inflightClosed int64 = 1 << 62
+
+// inflight 统计已登记但尚未结束的任务（以及周期任务等后台提交者），取代 sync.WaitGroup。
+//
+// WaitGroup 要求计数从 0 开始增加的 Add 发生在 Wait 之前，Submit 与 Wait 并发属于未定义用法。
+// inflight 将计数与关闭状态放在同一个原子变量中，由此给出确定的约定：
+//   - 计数大于 0 时登记总是成功，Wait 会等待新登记的任务（任务中派生子任务因此是安全的）
+//   - 计数归零且 Wait 已开始时池被关闭，此后的登记一律返回 ErrPoolClosed
+type inflight struct {
+	state atomic.Int64
+	// stopped 表示已停止接收新的登记（见 StopAccepting），已登记的计数照常释放
+	stopped atomic.Bool
+	mu      sync.Mutex
+	// zero 在计数归零时广播，唤醒等待关闭的 Wait
+	zero sync.Cond
+}
+
+// init 初始化条件变量，由 New 调用。
+func (c *inflight) init() {
+	c.zero.L = &c.mu
+}
+
+// add 登记 n 个在途计数；池已关闭或已停止接收时返回 ErrPoolClosed，不登记任何计数。
+func (c *inflight) add(n int) error {
+	if c.stopped.Load() {
+		return ErrPoolClosed
+	}
+	for {
+		s := c.state.Load()
+		if s&inflightClosed != 0 {
+			return ErrPoolClosed
+		}
+		if c.state.CompareAndSwap(s, s+int64(n)) {
+			return nil
+		}
+	}
+}
+
+// done 释放 n 个在途计数，计数归零时唤醒等待中的 Wait。
+func (c *inflight) done(n int) {
+	switch s := c.state.Add(-int64(n)); {
+	case s == 0:
+		c.mu.Lock()
+		c.zero.Broadcast()
+		c.mu.Unlock()
+	case s < 0:
+		panic("gopoolx: negative in-flight count")
+	}
+}
+
+// close 阻塞直到计数归零，随后将池标记为关闭。并发调用是安全的：
+// 所有调用都会等到池被关闭后返回，只有真正完成关闭的那次调用返回 true。
+func (c *inflight) close() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		s := c.state.Load()
+		if s&inflightClosed != 0 {
+			return false
+		}
+		if s == 0 && c.state.CompareAndSwap(0, inflightClosed) {
+			c.zero.Broadcast()
+			return true
+		}
+		c.zero.Wait()
+	}
+}
@@ -0,0 +1,70 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// newRunningPool 创建并启动一个池，测试结束时取消其 ctx。
+func newRunningPool(t *testing.T, workers int, opts ...Option) *Pool {
+	t.Helper()
+	p := New(workers, append([]Option{WithQueueSize(64)}, opts...)...)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go p.Run(ctx)
+	return p
+}
+
+// TestSubmitRacingClose 并发地提交与关闭池：每次提交要么其任务被执行，要么返回 ErrPoolClosed，
+// 不会 panic，也不会有被接受却从未执行的任务。
+func TestSubmitRacingClose(t *testing.T) {
+	closers := map[string]func(p *Pool){
+		"Wait":     func(p *Pool) { p.Wait() },
+		"Shutdown": func(p *Pool) { p.Shutdown(context.Background()) },
+	}
+	for name, closePool := range closers {
+		t.Run(name, func(t *testing.T) {
+			for round := 0; round < 50; round++ {
+				p := newRunningPool(t, 4)
+				const submitters, perSubmitter = 8, 50
+				var accepted, rejected, ran atomic.Int64
+				var start, wg sync.WaitGroup
+				start.Add(1)
+				for i := 0; i < submitters; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						start.Wait()
+						for j := 0; j < perSubmitter; j++ {
+							err := p.Submit(func(context.Context) error {
+								ran.Add(1)
+								return nil
+							})
+							switch {
+							case err == nil:
+								accepted.Add(1)
+							case errors.Is(err, ErrPoolClosed):
+								rejected.Add(1)
+							default:
+								t.Errorf("Submit returned %v", err)
+							}
+						}
+					}()
+				}
+				start.Done()
+				closePool(p)
+				wg.Wait()
+				// 关闭之后的提交全部被拒绝，被接受的任务都已在关闭前执行完
+				if got := accepted.Load() + rejected.Load(); got != submitters*perSubmitter {
+					t.Fatalf("accepted+rejected = %d, want %d", got, submitters*perSubmitter)
+				}
+				if ran.Load() != accepted.Load() {
+					t.Fatalf("ran %d tasks, accepted %d", ran.Load(), accepted.Load())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package gopoolx
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// WithInlineThreshold 允许在池饱和时于提交方直接执行很短的任务：等待模式（QueueFullWait）下
+// 任务无法立即交给 worker（队列已满或没有空闲 worker），而最近执行的任务平均耗时（指数滑动平均）
+// 低于 d 时，Submit 不再阻塞等待，而是在调用方 goroutine 中执行该任务后返回。
+// 对大量微任务的负载，这用严格的异步性换取更少的 goroutine 切换；任务的重试、panic 恢复、
+// 统计与回调照常进行，并计入 Stats().Inlined。
+//
+// 在池执行过第一个任务之前、Run 之前不会内联；其他队列满策略与自定义队列不受影响。
+// 内联的任务使用 Run 的 ctx，提交方因此会被任务的执行时间阻塞，d 应远小于提交方可接受的延迟。
+// d <= 0 表示不启用（默认）。
+func WithInlineThreshold(d time.Duration) Option {
+	return func(o *Options) {
+		o.inlineThreshold = d
+	}
+}
+
+// inlineExec 是内联执行的状态。
+type inlineExec struct {
+	threshold time.Duration
+	// avg 是最近执行耗时的滑动平均（纳秒），< 0 表示尚无样本
+	avg atomic.Int64
+	// ctx 是 Run 的 ctx，Run 之前为 nil
+	ctx atomic.Pointer[context.Context]
+}
+
+// newInlineExec 按阈值 d 创建内联执行状态。
+func newInlineExec(d time.Duration) *inlineExec {
+	in := &inlineExec{threshold: d}
+	in.avg.Store(-1)
+	return in
+}
+
+// observe 以一次执行耗时更新滑动平均（权重 1/8）。并发更新可能丢失个别样本，对估计无影响。
+func (in *inlineExec) observe(elapsed time.Duration) {
+	old := in.avg.Load()
+	if old < 0 {
+		in.avg.Store(int64(elapsed))
+		return
+	}
+	in.avg.Store(old + (int64(elapsed)-old)/8)
+}
+
+// tryInline 在满足内联条件时于当前 goroutine 执行已计入排队的任务 j，返回是否已执行。
+func (p *Pool) tryInline(j *job) bool {
+	in := p.inline
+	if in == nil {
+		return false
+	}
+	ctx := in.ctx.Load()
+	if ctx == nil {
+		return false
+	}
+	if avg := in.avg.Load(); avg < 0 || time.Duration(avg) >= in.threshold {
+		return false
+	}
+	p.addQueued(-1)
+	p.stats.inlined.Add(1)
+	p.exec(*ctx, j)
+	return true
+}
@@ -0,0 +1,87 @@
+package gopoolx
+
+import (
+	"maps"
+	"slices"
+)
+
+// WithName 为池命名，使同一进程中多个池的输出可以区分：名称会出现在任务的最终错误（*TaskError.Pool）、
+// 任务快照（TaskInfo.Pool，结束回调与错误上报方中同样可见）、span 属性与按池区分的指标中
+// （见 LabeledMetricsSink、SpanAttributer）。命名池中匿名任务的最终错误同样会被包装为 *TaskError，
+// 判断错误时应使用 errors.Is / errors.As。
+func WithName(name string) Option {
+	return func(o *Options) {
+		o.name = name
+	}
+}
+
+// WithLabels 为池附加一组标签（如 "tenant"、"component"），与 WithName 一起用于区分指标与追踪数据。
+// labels 会被复制，之后修改它不影响池。
+func WithLabels(labels map[string]string) Option {
+	return func(o *Options) {
+		o.labels = maps.Clone(labels)
+	}
+}
+
+// Name 返回池的名称（见 WithName），未命名时为空字符串。
+func (p *Pool) Name() string {
+	return p.opts.name
+}
+
+// Labels 返回池标签的副本（见 WithLabels），未设置时返回 nil。
+func (p *Pool) Labels() map[string]string {
+	return maps.Clone(p.opts.labels)
+}
+
+// identity 返回池的名称与标签合并后的标签集合，名称以 "pool" 为键；二者都未设置时返回 nil。
+func (p *Pool) identity() map[string]string {
+	if p.opts.name == "" && len(p.opts.labels) == 0 {
+		return nil
+	}
+	ids := maps.Clone(p.opts.labels)
+	if ids == nil {
+		ids = make(map[string]string, 1)
+	}
+	if p.opts.name != "" {
+		ids["pool"] = p.opts.name
+	}
+	return ids
+}
+
+// LabeledMetricsSink 是可以按池区分指标的 MetricsSink。池设置了名称或标签时，会在创建时以
+// identity（名称以 "pool" 为键，与 WithLabels 的标签合并）调用一次 ForPool，此后改用返回的 sink 上报，
+// Prometheus 等实现可以借此为该池的所有指标固定一组标签值。
+type LabeledMetricsSink interface {
+	MetricsSink
+	ForPool(identity map[string]string) MetricsSink
+}
+
+// SpanAttributer 是可选的 Span 扩展。池设置了名称或标签时，实现了它的 span 在开始后
+// 会以 "pool" 与各标签为属性调用一次 SetAttributes。
+type SpanAttributer interface {
+	SetAttributes(attrs ...Attr)
+}
+
+// labelMetrics 在池设置了名称或标签且 sink 实现了 LabeledMetricsSink 时，替换为按池区分的 sink。
+func (p *Pool) labelMetrics() {
+	ls, ok := p.opts.metrics.(LabeledMetricsSink)
+	if !ok {
+		return
+	}
+	if ids := p.identity(); ids != nil {
+		p.opts.metrics = ls.ForPool(ids)
+	}
+}
+
+// identityAttrs 返回写入 span 的池属性，属性顺序按键排序以保持稳定。
+func (p *Pool) identityAttrs() []Attr {
+	ids := p.identity()
+	if ids == nil {
+		return nil
+	}
+	attrs := make([]Attr, 0, len(ids))
+	for _, k := range slices.Sorted(maps.Keys(ids)) {
+		attrs = append(attrs, Attr{Key: k, Value: ids[k]})
+	}
+	return attrs
+}
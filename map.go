@@ -0,0 +1,56 @@
+package gopoolx
+
+import (
+	"context"
+	"slices"
+)
+
+// Result 表示单个元素的处理结果，用于流式返回结果的场景。
+type Result[T any] struct {
+	// Index 是元素在输入中的下标
+	Index int
+	// Value 是处理成功时的结果，失败时为零值
+	Value T
+	// Err 是处理失败时的错误
+	Err error
+}
+
+// Map 使用 pool 并发地对 items 中的每个元素执行 fn，
+// 返回与输入下标一一对应的结果，以及按输入顺序聚合的错误。
+// 它等价于使用 context.Background() 调用 FanOut。
+func Map[T, R any](
+	pool *Pool,
+	items []T,
+	fn func(ctx context.Context, item T) (R, error),
+) ([]R, error) {
+	return FanOut(context.Background(), pool, items, fn)
+}
+
+// MapUnordered 使用 pool 并发地对 items 中的每个元素执行 fn，
+// 按完成顺序通过通道流式返回结果，Result.Index 标识对应的输入下标。
+// 通道无缓冲，结果不会在内存中堆积：调用方必须持续消费直到通道关闭，
+// 否则 worker 会阻塞在发送结果上。所有元素处理结束后通道关闭。
+func MapUnordered[T, R any](
+	pool *Pool,
+	items []T,
+	fn func(ctx context.Context, item T) (R, error),
+) <-chan Result[R] {
+	out := make(chan Result[R])
+	go func() {
+		defer close(out)
+		runEach(context.Background(), pool, slices.All(items), func(ctx context.Context, i int, item T) error {
+			res, err := fn(ctx, item)
+			if err != nil {
+				return err
+			}
+			out <- Result[R]{Index: i, Value: res}
+			return nil
+		}, func(i int, err error) {
+			// 成功的结果已在任务中发送，这里只发送最终失败
+			if err != nil {
+				out <- Result[R]{Index: i, Err: err}
+			}
+		})
+	}()
+	return out
+}
@@ -0,0 +1,107 @@
+package gopoolx
+
+import (
+	"errors"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// ErrMemoryPressure 表示进程内存已达到 WithMemoryPressurePolicy 设置的上限，提交被拒绝。
+var ErrMemoryPressure = errors.New("memory pressure: submission rejected")
+
+// memPollInterval 是内存用量的采样间隔：两次采样之间的提交复用上一次的读数，
+// 等待模式下被推迟的提交也按该间隔重新检查。
+const memPollInterval = 10 * time.Millisecond
+
+// WithMemoryPressurePolicy 启用基于内存的准入控制：进程内存用量达到 limitBytes 时，
+// 新的提交按队列满策略处理——等待模式下阻塞到内存回落到上限以下（或 Run 的 ctx 结束），
+// 丢弃模式下丢弃任务，返回错误模式下返回 ErrMemoryPressure 并计入 Errors。
+// 用于防止等待模式配合很大的 queueSize 时，队列无限增长导致 OOM。
+//
+// 内存用量默认取 Go 运行时向操作系统申请、且尚未归还的内存（与 GOMEMLIMIT 统计的口径一致），
+// 可以通过 WithMemoryGauge 改为自定义读数（如 cgroup 的内存用量）。读数每 10ms 至多刷新一次，
+// 因此上限是软限制。limitBytes 为 0 表示不启用。
+func WithMemoryPressurePolicy(limitBytes uint64) Option {
+	return func(o *Options) {
+		o.memoryLimit = limitBytes
+	}
+}
+
+// WithMemoryGauge 以 gauge 的返回值（字节）作为 WithMemoryPressurePolicy 判断的内存用量。
+// gauge 在提交方中调用，需并发安全且开销很小。
+func WithMemoryGauge(gauge func() uint64) Option {
+	return func(o *Options) {
+		o.memoryGauge = gauge
+	}
+}
+
+// memoryGuard 缓存内存用量的读数，供提交时判断是否超过上限。
+type memoryGuard struct {
+	limit uint64
+	gauge func() uint64
+	// used 是最近一次读取的内存用量，readAt 是读取时间（UnixNano）
+	used   atomic.Uint64
+	readAt atomic.Int64
+}
+
+// newMemoryGuard 按配置创建内存准入状态，未启用时返回 nil。
+func newMemoryGuard(o *Options) *memoryGuard {
+	if o.memoryLimit == 0 {
+		return nil
+	}
+	g := &memoryGuard{limit: o.memoryLimit, gauge: o.memoryGauge}
+	if g.gauge == nil {
+		g.gauge = runtimeMemory
+	}
+	return g
+}
+
+// runtimeMemory 返回 Go 运行时从操作系统获得、且尚未归还的内存字节数。
+func runtimeMemory() uint64 {
+	s := [2]metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(s[:])
+	return sampleUint(s[0]) - sampleUint(s[1])
+}
+
+// over 判断内存用量是否已达到上限，读数过期时由抢到刷新权的调用方重新读取。
+func (g *memoryGuard) over(now time.Time) bool {
+	last := g.readAt.Load()
+	if now.UnixNano()-last >= int64(memPollInterval) && g.readAt.CompareAndSwap(last, now.UnixNano()) {
+		g.used.Store(g.gauge())
+	}
+	return g.used.Load() >= g.limit
+}
+
+// admitMemory 在内存用量达到上限时按队列满策略处理一次提交，未启用或未超限时返回 nil。
+func (p *Pool) admitMemory() error {
+	g := p.memory
+	if g == nil || !g.over(p.opts.clock.Now()) {
+		return nil
+	}
+	switch p.queueFullPolicy() {
+	case QueueFullDiscard:
+		return ErrDiscarded
+	case QueueFullReturnError:
+		p.errs.Add(ErrMemoryPressure)
+		return ErrMemoryPressure
+	}
+
+	// 等待模式：推迟提交，直到内存回落或 worker 已退出
+	timer := p.opts.clock.NewTimer(memPollInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C():
+		case <-p.quit:
+			return ErrPoolClosed
+		}
+		if !g.over(p.opts.clock.Now()) {
+			return nil
+		}
+		timer.Reset(memPollInterval)
+	}
+}
@@ -0,0 +1,66 @@
+package gopoolx
+
+import "time"
+
+// 池通过 MetricsSink 上报的指标名。
+const (
+	// MetricTasksSucceeded / MetricTasksFailed 是执行成功与最终失败的任务数（计数器）
+	MetricTasksSucceeded = "gopoolx_tasks_succeeded_total"
+	MetricTasksFailed    = "gopoolx_tasks_failed_total"
+	// MetricTasksSkipped 是出队时 ctx 已结束而未执行的任务数，
+	// MetricTasksStale 是因排队过久（WithMaxQueueAge）被丢弃的任务数（计数器）
+	MetricTasksSkipped = "gopoolx_tasks_skipped_total"
+	MetricTasksStale   = "gopoolx_tasks_stale_total"
+	// MetricRetries 是重试次数，不含首次执行（计数器）
+	MetricRetries = "gopoolx_retries_total"
+	// MetricQueueWait / MetricExecDuration 是任务的排队时长与执行时长（含重试）
+	MetricQueueWait    = "gopoolx_queue_wait_duration"
+	MetricExecDuration = "gopoolx_exec_duration"
+	// MetricQueueDepth / MetricRunning 是排队中与执行中的任务数（仪表）
+	MetricQueueDepth = "gopoolx_queue_depth"
+	MetricRunning    = "gopoolx_running"
+)
+
+// MetricsSink 是与具体监控系统无关的指标接收方，Prometheus、OpenTelemetry、StatsD
+// 或自定义管道只需实现这三个方法即可接入。方法在 worker 与提交方中同步调用，
+// 必须并发安全且开销很小。多个池共享同一个 sink 时，可在实现中为指标加上区分池的前缀或标签。
+type MetricsSink interface {
+	// IncCounter 将计数器 name 增加 delta
+	IncCounter(name string, delta uint64)
+	// ObserveDuration 记录一次 name 的耗时
+	ObserveDuration(name string, d time.Duration)
+	// SetGauge 将仪表 name 设置为 value
+	SetGauge(name string, value float64)
+}
+
+// WithMetricsSink 让池把运行指标（见 Metric* 常量）实时上报给 sink。
+// 与 Stats 的轮询不同，sink 在每个事件发生时被调用：任务结束时上报结果、重试次数与耗时，
+// 队列深度与执行中任务数变化时更新仪表。
+func WithMetricsSink(sink MetricsSink) Option {
+	return func(o *Options) {
+		o.metrics = sink
+	}
+}
+
+// reportRun 向 sink 上报一个执行结束的任务。
+func reportRun(m MetricsSink, err error, attempts int, waited, elapsed time.Duration) {
+	if err != nil {
+		m.IncCounter(MetricTasksFailed, 1)
+	} else {
+		m.IncCounter(MetricTasksSucceeded, 1)
+	}
+	if attempts > 1 {
+		m.IncCounter(MetricRetries, uint64(attempts-1))
+	}
+	m.ObserveDuration(MetricQueueWait, waited)
+	m.ObserveDuration(MetricExecDuration, elapsed)
+}
+
+// reportSkipped 向 sink 上报一个出队后未执行的任务。
+func reportSkipped(m MetricsSink, status TaskStatus) {
+	if status == TaskDiscarded {
+		m.IncCounter(MetricTasksStale, 1)
+	} else {
+		m.IncCounter(MetricTasksSkipped, 1)
+	}
+}
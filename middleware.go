@@ -0,0 +1,64 @@
+package gopoolx
+
+import "context"
+
+// Middleware 包裹任务的每一次执行（包括每次重试），返回新的任务，
+// 用法与 HTTP 中间件相同，适合实现鉴权刷新、指标、日志、链路追踪、超时等横切逻辑，
+// 而不必在每个任务中重复编写。
+type Middleware func(next Task) Task
+
+// WithMiddleware 为池添加中间件，可多次调用。先添加的中间件位于外层，
+// 即 WithMiddleware(a, b) 时执行顺序为 a → b → 任务。
+// 中间件在限流、熔断判断通过之后执行；启用 WithHardTimeout 时与任务一起在独立的 goroutine 中执行。
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *Options) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// Recovery 是内置 panic 恢复的中间件形式：将任务中的 panic 转换为 error 返回。
+// 与内置恢复（panic 时直接结束任务）不同，作为中间件时该错误与普通失败一样参与重试。
+// 可通过 WithRecovery(Recovery) 使用，或在自定义的恢复中间件中复用。
+func Recovery(next Task) Task {
+	return func(ctx context.Context) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panicError(r)
+			}
+		}()
+		return next(ctx)
+	}
+}
+
+// WithRecovery 以 mw 替换内置的 panic 恢复，mw 作为最外层的中间件包裹每次执行，
+// 例如在恢复时上报告警或打印堆栈。mw 为 nil 等同于 WithoutRecovery。
+func WithRecovery(mw Middleware) Option {
+	return func(o *Options) {
+		o.recovery = mw
+		o.customRecovery = true
+	}
+}
+
+// WithoutRecovery 关闭内置的 panic 恢复：任务中的 panic 不再被转换为 error，
+// 而是直接使进程崩溃，适合希望在开发环境中尽早暴露问题的团队，生产构建中保留默认恢复即可。
+// SubmitWithResult 等带返回值的提交不受影响，panic 仍会作为结果交付给调用方。
+func WithoutRecovery() Option {
+	return WithRecovery(nil)
+}
+
+// wrap 用配置的中间件（以及替换内置恢复的中间件）包裹 r，未配置时原样返回。
+func (p *Pool) wrap(r runner) runner {
+	mws := p.opts.middleware
+	recovery := p.opts.recovery
+	if len(mws) == 0 && recovery == nil {
+		return r
+	}
+	t := Task(r.run)
+	for i := len(mws) - 1; i >= 0; i-- {
+		t = mws[i](t)
+	}
+	if recovery != nil {
+		t = recovery(t)
+	}
+	return t
+}
@@ -0,0 +1,56 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TaskError 是命名任务或经过重试的任务的最终错误，携带出错任务的名称与执行次数。
+// 可以通过 errors.As 取出这些信息，通过 errors.Is / errors.Unwrap 访问原始错误。
+type TaskError struct {
+	// Pool 是任务所在池的名称（见 WithName），未命名的池为空
+	Pool string
+	// Name 是出错任务的名称，匿名任务为空
+	Name string
+	// Attempts 是任务实际被执行的次数（首次执行 + 重试），未知时为 0
+	Attempts int
+	// Err 是任务返回的原始错误（panic 时为转换后的 error）
+	Err error
+}
+
+// Error 实现 error 接口。
+func (e *TaskError) Error() string {
+	task := "task"
+	if e.Name != "" {
+		task = fmt.Sprintf("task %q", e.Name)
+	}
+	if e.Pool != "" {
+		task = fmt.Sprintf("pool %q: %s", e.Pool, task)
+	}
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s failed after %d attempts: %v", task, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", task, e.Err)
+}
+
+// Unwrap 返回原始错误。
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// NamedTask 为 task 绑定名称，返回的任务所返回的错误会被包装为 *TaskError。
+// 适用于 Graph.Add 等接受 Task 但不接受 SubmitOption 的场景；
+// 提交到池时更推荐使用 WithTaskName，它同样覆盖 panic 并会记录在 TaskInfo 中。
+func NamedTask(name string, task Task) Task {
+	return func(ctx context.Context) error {
+		if err := task(ctx); err != nil {
+			var te *TaskError
+			if errors.As(err, &te) && te.Name == name {
+				return err
+			}
+			return &TaskError{Name: name, Err: err}
+		}
+		return nil
+	}
+}
@@ -2,6 +2,7 @@ package gopoolx
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -17,9 +18,77 @@ const (
 	QueueFullReturnError
 )
 
-// ErrQueueFull 表示队列已满的错误
+// String 返回策略的可读名称。
+func (p QueueFullPolicy) String() string {
+	switch p {
+	case QueueFullWait:
+		return "wait"
+	case QueueFullDiscard:
+		return "discard"
+	case QueueFullReturnError:
+		return "return-error"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText 以 String 的名称编码策略，便于在 JSON、YAML 等配置文件中使用。
+func (p QueueFullPolicy) MarshalText() ([]byte, error) {
+	if p < QueueFullWait || p > QueueFullReturnError {
+		return nil, fmt.Errorf("unknown QueueFullPolicy %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText 解析 String 返回的名称（"wait"、"discard"、"return-error"）。
+func (p *QueueFullPolicy) UnmarshalText(text []byte) error {
+	for _, v := range []QueueFullPolicy{QueueFullWait, QueueFullDiscard, QueueFullReturnError} {
+		if string(text) == v.String() {
+			*p = v
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown QueueFullPolicy %q", text)
+}
+
+// ErrQueueFull 表示队列已满的错误。池返回的是包装了它的 *QueueFullError，
+// 判断时应使用 errors.Is(err, ErrQueueFull)。
 var ErrQueueFull = errors.New("task queue is full")
 
+// QueueFullError 是队列已满时返回的错误，携带出错时队列的状态，
+// 便于从饱和池的日志中判断队列应当设置多大。它包装了 ErrQueueFull。
+type QueueFullError struct {
+	// Len 是出错时队列中的任务数
+	Len int
+	// Cap 是队列容量（WithQueueSize）
+	Cap int
+	// Policy 是出错时生效的队列满策略
+	Policy QueueFullPolicy
+}
+
+// Error 实现 error。
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("%v (len=%d, cap=%d, policy=%v)", ErrQueueFull, e.Len, e.Cap, e.Policy)
+}
+
+// Unwrap 返回 ErrQueueFull。
+func (e *QueueFullError) Unwrap() error {
+	return ErrQueueFull
+}
+
+// ErrMaxPending 表示在途任务数已达到 WithMaxPending 设置的上限
+var ErrMaxPending = errors.New("too many pending tasks")
+
+// ErrPoolClosed 表示池已关闭，不再接受新的任务
+var ErrPoolClosed = errors.New("pool is closed")
+
+// ErrDiscarded 表示任务按 QueueFullDiscard 策略被丢弃、不会被执行。
+// Submit 在丢弃模式下仍返回 nil，该错误只用于通知 Future 等上层封装。
+var ErrDiscarded = errors.New("task discarded")
+
+// ErrStale 表示任务在队列中等待超过 WithMaxQueueAge 设定的时长，被直接丢弃而未执行。
+var ErrStale = errors.New("task expired in queue")
+
 // Options 封装了 Pool 的可配置项。
 type Options struct {
 	// retry 表示在任务执行失败时，最多额外重试的次数。
@@ -27,6 +96,33 @@ type Options struct {
 	retry int
 	// retryDelay 是每次重试之间的等待时间。
 	retryDelay time.Duration
+	// retryDelayFunc 根据执行次数与错误计算重试前的等待时间，设置后优先于 retryDelay。
+	retryDelayFunc func(attempt int, err error) time.Duration
+	// middleware 是包裹每次执行的中间件，先添加的位于外层。
+	middleware []Middleware
+	// customRecovery 表示内置的 panic 恢复已被 recovery 替换（recovery 为 nil 时表示关闭恢复）。
+	customRecovery bool
+	recovery       Middleware
+	// errorDedup 表示错误收集器对相同的错误信息去重。
+	errorDedup bool
+	// errorLimit 是错误收集器保留的详细错误数上限，0 表示不限制；
+	// errorSummarize 表示超出上限的错误仍按信息计入 ErrorsSummary。
+	errorLimit     int
+	errorSummarize bool
+	// deadlinePropagation 表示从任务的截止时间中扣除排队时长，见 WithDeadlinePropagation。
+	deadlinePropagation bool
+	// reporter 接收任务的最终错误与 panic，未设置时为 nil，见 WithErrorReporter。
+	reporter Reporter
+	// tracer 为每个执行的任务开启 span，未设置时为 nil，见 WithTracer。
+	tracer Tracer
+	// metrics 是接收运行指标的 sink，未设置时为 nil，见 WithMetricsSink。
+	metrics MetricsSink
+	// watermarks 是队列深度的高低水位回调，未启用时为 nil，见 WithQueueWatermarks。
+	watermarks *watermarks
+	// onTaskComplete 在每个出队的任务结束时被调用，见 WithOnTaskComplete。
+	onTaskComplete func(info TaskInfo)
+	// retryBudget 是重试次数相对首次执行次数的上限比例，0 表示不限制。
+	retryBudget float64
 
 	// queueSize 指任务队列（chan Task）的缓冲大小。
 	//   - 0 表示无缓冲通道（提交和消费完全同步）
@@ -37,6 +133,101 @@ type Options struct {
 	//   - QueueFullDiscard: 直接丢弃任务
 	//   - QueueFullReturnError: 返回错误，任务计入失败
 	queueFullPolicy QueueFullPolicy
+	// maxPending 限制已提交但尚未结束的任务数（排队中 + 执行中），0 表示不限制。
+	maxPending int
+	// atomicBatch 表示 SubmitAll 在返回错误模式下整批接受或整批拒绝。
+	atomicBatch bool
+	// trackTasks 表示为每个提交的任务创建句柄，使其出现在 Tasks 中。
+	trackTasks bool
+	// taskHistory 是 Tasks 中保留的最近结束任务数量。
+	taskHistory int
+	// maxQueueAge 是任务在队列中允许等待的最长时间，0 表示不限制。
+	maxQueueAge time.Duration
+	// onStale 在任务因排队过久被丢弃时调用，可为 nil。
+	onStale func(name string, waited time.Duration)
+	// latencyBuckets 是耗时直方图的桶上界，为 nil 表示不记录耗时。
+	latencyBuckets []time.Duration
+	// clock 是池使用的时钟，默认为系统时钟。
+	clock Clock
+	// synchronous 表示提交时在调用方 goroutine 中直接执行任务（测试模式）。
+	synchronous bool
+	// dequeueBatch 是 worker 每次最多从队列取走的任务数，<= 1 表示逐个取。
+	dequeueBatch int
+	// dispatchMode 是任务分派到 worker 的方式，见 WithDispatchMode。
+	dispatchMode DispatchMode
+	// procsMultiplier 是 worker 数相对 GOMAXPROCS 的倍数，<= 0 表示不跟随，见 WithGOMAXPROCSTracking。
+	procsMultiplier float64
+	// lockOSThread 表示每个 worker 锁定到一个操作系统线程，见 WithLockOSThread。
+	lockOSThread bool
+	// onWorkerStart / onWorkerStop 在 worker 启动与退出时调用，见 WithWorkerHooks。
+	onWorkerStart func()
+	onWorkerStop  func()
+	// onWorkerRestart 在 worker 意外退出并被替换时调用，见 WithOnWorkerRestart。
+	onWorkerRestart func(reason any)
+	// sampling 是按任务的资源采样，nil 表示不采样，见 WithResourceSampling。
+	sampling *resourceSampler
+	// memoryLimit 是准入控制的内存上限，0 表示不启用；memoryGauge 是自定义的内存读数，
+	// 见 WithMemoryPressurePolicy、WithMemoryGauge。
+	memoryLimit uint64
+	memoryGauge func() uint64
+	// name 与 labels 是池的名称与标签，见 WithName、WithLabels。
+	name   string
+	labels map[string]string
+	// inlineThreshold 是在提交方内联执行的平均耗时上限，<= 0 表示不内联，见 WithInlineThreshold。
+	inlineThreshold time.Duration
+	// coalesceWindow 是按键合并提交的窗口长度，<= 0 表示不合并，见 WithCoalesceWindow。
+	coalesceWindow time.Duration
+	// workerBuffer 创建每个 worker 的暂存对象，为 nil 表示不启用，见 WithWorkerBuffer。
+	workerBuffer func() any
+	// ordering 是执行顺序保证，见 WithOrdering。
+	ordering Ordering
+	// admissionFilter 在任务入队前检查是否接受它，为 nil 表示不过滤，见 WithAdmissionFilter。
+	admissionFilter func(info TaskInfo) error
+	// retryRequeue 表示等待重试的任务回到延迟队列而不是占着 worker，见 WithRetryRequeue。
+	retryRequeue bool
+	// governor 是跨池的全局并发上限，为 nil 表示不启用，见 WithGovernor。
+	governor *Governor
+	// ephemeralWorkers 表示每个任务在新的 goroutine 中执行，见 WithEphemeralWorkers。
+	ephemeralWorkers bool
+	// retryOnPanic 表示 panic 是否参与重试，retryOnPanicSet 表示它由 WithRetryOnPanic 显式设置。
+	retryOnPanic    bool
+	retryOnPanicSet bool
+	// autoIdempotencyKeys 表示为没有幂等键的任务自动生成一个，见 WithAutoIdempotencyKeys。
+	autoIdempotencyKeys bool
+	// prefetch 是 Serve 在空闲 worker 之外预先拉取的任务数，见 WithPrefetch。
+	prefetch int
+	// idleAfter 与 onIdle 是空闲检测的时长与回调，见 WithOnIdle。
+	idleAfter time.Duration
+	onIdle    func()
+	// reentrant 是 worker 中的任务在队列已满时向本池提交的处理策略。
+	reentrant ReentrantPolicy
+	// reentrantSet 表示 reentrant 由 WithReentrantPolicy 显式设置，而不是默认值。
+	reentrantSet bool
+	// hardTimeout 是单次执行的硬超时，0 表示不启用。
+	hardTimeout time.Duration
+	// onAbandon 在任务因硬超时被放弃时调用，可为 nil。
+	onAbandon func(name string)
+	// overflowPool 是队列已满时接收溢出任务的池，nil 表示不溢出。
+	overflowPool *Pool
+	// queue 是自定义的任务队列，nil 表示使用默认的 Go 通道。
+	queue TaskQueue
+	// persistDir 是持久化队列的日志目录，空字符串表示不启用。
+	persistDir string
+	// persistDecode 将持久化的任务数据还原为任务。
+	persistDecode TaskDecoder
+
+	// circuitThreshold 是触发熔断的连续失败次数，0 表示不启用熔断器。
+	circuitThreshold int
+	// circuitCooldown 是熔断器打开后的冷却时间。
+	circuitCooldown time.Duration
+	// circuitRatio 与 circuitWindow 是按失败率熔断的阈值与统计窗口，见 WithCircuitBreakerRate。
+	circuitRatio  float64
+	circuitWindow int
+
+	// limiter 在每次执行任务（包括重试）前被调用，nil 表示不限流。
+	limiter Limiter
+	// laneLimiters 是按 lane 划分的限流器，在全局限流器之前调用。
+	laneLimiters map[string]Limiter
 }
 
 // Option 是修改 Options 的函数式配置。
@@ -56,6 +247,66 @@ func WithRetryDelay(d time.Duration) Option {
 	}
 }
 
+// WithRetryDelayFunc 使用 fn 计算每次重试前的等待时间，设置后优先于 WithRetryDelay。
+// attempt 是刚刚失败的那次执行的序号（从 1 开始），err 是该次执行返回的错误，
+// 便于实现指数退避，或按错误类型采用服务端给出的 "retry after" 提示。
+// 返回值 <= 0 表示立即重试。
+func WithRetryDelayFunc(fn func(attempt int, err error) time.Duration) Option {
+	return func(o *Options) {
+		o.retryDelayFunc = fn
+	}
+}
+
+// WithRetryOnPanic 指定 panic 是否参与重试。retry 为 true 时，被恢复的 panic 与普通错误一样按 WithRetry 重试；
+// 为 false 时 panic 直接使任务失败，不再重试，无论它由内置恢复、Recovery 中间件还是 WithHardTimeout 的执行 goroutine 恢复。
+// 两种情况下 panic 的那次执行都计入执行次数（见 TaskError.Attempts）。
+//
+// 未设置时保持原有行为：内置恢复捕获的 panic 直接结束任务，Recovery 中间件等转换得到的错误照常重试。
+// 内置恢复被 WithoutRecovery 关闭时 panic 仍会使进程崩溃。
+func WithRetryOnPanic(retry bool) Option {
+	return func(o *Options) {
+		o.retryOnPanic = retry
+		o.retryOnPanicSet = true
+	}
+}
+
+// WithErrorDedup 让池的错误收集器对错误信息相同的错误去重：Errors 只保留每种信息的第一次出现，
+// 出现次数通过 ErrorsSummary 获取。上万条 "connection refused" 只是噪音，一条带计数的记录才是信号。
+func WithErrorDedup() Option {
+	return func(o *Options) {
+		o.errorDedup = true
+	}
+}
+
+// ErrorCollectionOption 是 WithErrorCollection 的配置项。
+type ErrorCollectionOption func(*Options)
+
+// LimitN 让错误收集器最多保留 n 个详细错误，之后的错误只计数（见 Stats().Errors 与 ErrorsDropped），
+// 使长期运行的池在持续失败时内存占用有界。n <= 0 表示不限制。
+func LimitN(n int) ErrorCollectionOption {
+	return func(o *Options) {
+		o.errorLimit = n
+	}
+}
+
+// SummarizeRest 让超出 LimitN 上限的错误仍按错误信息计入 ErrorsSummary，
+// 只是不再保留错误本身。
+func SummarizeRest() ErrorCollectionOption {
+	return func(o *Options) {
+		o.errorSummarize = true
+	}
+}
+
+// WithErrorCollection 配置池的错误收集方式，例如
+// WithErrorCollection(LimitN(1000), SummarizeRest())。
+func WithErrorCollection(opts ...ErrorCollectionOption) Option {
+	return func(o *Options) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
+
 // defaultOptions 返回 Pool 的默认配置。
 func defaultOptions() *Options {
 	return &Options{
@@ -63,6 +314,8 @@ func defaultOptions() *Options {
 		retryDelay:      0,
 		queueSize:       0,             // 0 = 无缓冲（最安全）
 		queueFullPolicy: QueueFullWait, // 默认等待策略
+		clock:           realClock{},
+		reentrant:       ReentrantOverflow,
 	}
 }
 
@@ -73,6 +326,17 @@ func WithQueueSize(n int) Option {
 	}
 }
 
+// WithDequeueBatch 让 worker 每次最多从队列取走 n 个任务，在本地依次执行，
+// 以非阻塞的出队代替多路 select，摊薄海量小任务时的同步开销。
+// 只有队列中积压的任务多于 worker 数时才会批量取，队列较浅时仍逐个取，
+// 避免任务被压在某个 worker 本地而其他 worker 空闲。需要配合 WithQueueSize 使用；
+// n <= 1 表示不启用。
+func WithDequeueBatch(n int) Option {
+	return func(o *Options) {
+		o.dequeueBatch = n
+	}
+}
+
 // WithQueueFullPolicy 设置队列满时的处理策略。
 // 可选策略：
 //   - QueueFullWait: 等待，直到有空位再插入（默认）
@@ -83,3 +347,128 @@ func WithQueueFullPolicy(policy QueueFullPolicy) Option {
 		o.queueFullPolicy = policy
 	}
 }
+
+// WithCircuitBreaker 为任务执行启用熔断器。
+// 连续失败次数达到 threshold 后，熔断器打开，cooldown 时间内的执行（包括重试）
+// 都会被直接短路并返回 ErrCircuitOpen，避免池自身产生的重试风暴压垮下游；
+// 冷却结束后放行一次试探执行，成功则恢复正常，失败则继续熔断。
+// threshold <= 0 表示不启用。
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(o *Options) {
+		o.circuitThreshold = threshold
+		o.circuitCooldown = cooldown
+	}
+}
+
+// WithCircuitBreakerRate 启用按失败率熔断的熔断器：最近 window 次执行（含重试）中失败的比例达到 ratio
+// （如 0.5）后熔断器打开，cooldown 内的执行都被短路并返回 ErrCircuitOpen，其余行为与 WithCircuitBreaker 相同。
+// 与只看连续失败的 WithCircuitBreaker 不同，成功与失败交替出现、但整体错误率很高时同样会熔断。
+// 失败率在每次失败时检查，窗口记满 window 次执行之前不会触发；熔断器重新闭合后从空窗口开始统计。
+//
+// 可以与 WithCircuitBreaker 同时使用，任一条件满足即熔断，冷却时间取最后设置的值。
+// ratio <= 0 或 window <= 0 表示不启用。
+func WithCircuitBreakerRate(ratio float64, window int, cooldown time.Duration) Option {
+	return func(o *Options) {
+		o.circuitRatio = ratio
+		o.circuitWindow = window
+		o.circuitCooldown = cooldown
+	}
+}
+
+// WithRateLimit 使用内置令牌桶限制任务的启动速率。
+// rps 为每秒允许启动的任务数（每次重试同样计数），burst 为允许的最大突发量，
+// 与 worker 数量相互独立，例如 "20 个 worker，但每秒最多 50 次调用"。
+// rps <= 0 表示不限流。
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *Options) {
+		if rps <= 0 {
+			o.limiter = nil
+			return
+		}
+		o.limiter = newTokenBucket(rps, burst)
+	}
+}
+
+// WithLimiter 使用自定义限流器控制任务的启动速率，
+// 例如直接传入 golang.org/x/time/rate 的 *rate.Limiter。
+func WithLimiter(l Limiter) Option {
+	return func(o *Options) {
+		o.limiter = l
+	}
+}
+
+// WithLaneRateLimit 为指定 lane 的任务设置独立的令牌桶限流，
+// 同一个池可以安全地同时调用多个配额不同的第三方 API。
+// 通过 WithLane 提交的任务会先经过所属 lane 的限流，再经过全局限流（若有），
+// 因此等待 lane 令牌的任务不会占用全局令牌。注意：等待 lane 令牌期间任务会占用 worker。
+func WithLaneRateLimit(lane string, rps float64, burst int) Option {
+	return func(o *Options) {
+		if rps <= 0 {
+			delete(o.laneLimiters, lane)
+			return
+		}
+		WithLaneLimiter(lane, newTokenBucket(rps, burst))(o)
+	}
+}
+
+// WithLaneLimiter 为指定 lane 的任务设置自定义限流器。
+func WithLaneLimiter(lane string, l Limiter) Option {
+	return func(o *Options) {
+		if o.laneLimiters == nil {
+			o.laneLimiters = make(map[string]Limiter)
+		}
+		o.laneLimiters[lane] = l
+	}
+}
+
+// WithMaxPending 限制已提交但尚未执行完成的任务总数（排队中 + 执行中）。
+// 超出上限时 Submit 的行为与队列满策略一致：等待、丢弃或返回 ErrMaxPending。
+// 与 WithQueueSize 不同，它表达的是"任何时刻最多有 n 个未完成任务"。
+// n <= 0 表示不限制。
+func WithMaxPending(n int) Option {
+	return func(o *Options) {
+		o.maxPending = n
+	}
+}
+
+// WithMaxQueueAge 丢弃在队列中等待超过 d 的任务：worker 取出这类任务时不再执行，
+// 而是直接以 ErrStale 结束（不计入 Errors，计入 Stats.DroppedStale）。
+// 适用于请求级任务——排队太久时，发起请求的一方早已超时，执行只是浪费。
+// onStale 在任务被丢弃时以任务名（见 WithTaskName）和实际等待时长调用，可为 nil。
+// d <= 0 表示不限制。
+func WithMaxQueueAge(d time.Duration, onStale func(name string, waited time.Duration)) Option {
+	return func(o *Options) {
+		o.maxQueueAge = d
+		o.onStale = onStale
+	}
+}
+
+// WithSynchronous 启用同步模式：Submit 等提交接口直接在调用方 goroutine 中执行任务
+// （同样经过重试、限流、熔断与 panic 恢复），返回时任务已经结束；Run 和 Wait 不做任何事。
+// 延迟任务会忽略延迟立即执行。该模式用于单元测试依赖池的业务代码，
+// 使结果确定且无需 sleep；不适合生产环境。
+func WithSynchronous() Option {
+	return func(o *Options) {
+		o.synchronous = true
+	}
+}
+
+// WithAtomicBatch 使 SubmitAll 在 QueueFullReturnError 策略下具备原子语义：
+// 队列（以及 WithMaxPending 的在途名额）剩余空间不足以容纳整批任务时，
+// 整批拒绝并返回错误，不会只提交其中一部分。
+// 原子预留依赖带缓冲的队列：无缓冲队列没有可预留的空间，SubmitAll 退回逐个交付的普通语义。
+func WithAtomicBatch() Option {
+	return func(o *Options) {
+		o.atomicBatch = true
+	}
+}
+
+// WithTaskTracking 为每个提交的任务创建句柄，使所有任务都能通过 Tasks 查询，
+// 并通过 Cancel 按编号取消；history 为额外保留的最近结束任务数量（<= 0 表示不保留）。
+// 开启后每次提交会多一次句柄分配与登记，适合需要管理接口的场景。
+func WithTaskTracking(history int) Option {
+	return func(o *Options) {
+		o.trackTasks = true
+		o.taskHistory = history
+	}
+}
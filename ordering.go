@@ -0,0 +1,108 @@
+package gopoolx
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// Ordering 定义池对任务执行顺序的保证，见 WithOrdering。
+type Ordering int
+
+const (
+	// OrderUnordered 不保证执行顺序（默认）：空闲的 worker 取走任意一个就绪的任务，
+	// 重试、内联执行、溢出与合并等机制都可以让任务先于更早提交的任务执行
+	OrderUnordered Ordering = iota
+	// OrderFIFO 严格按提交顺序逐个执行：前一个任务（含全部重试）结束后才开始下一个，
+	// 要求池只有一个 worker
+	OrderFIFO
+	// OrderFIFOPerKey 对同一个键（见 WithKey）的任务按提交顺序逐个执行，不同键的任务并行执行；
+	// 未带键的任务不保证顺序
+	OrderFIFOPerKey
+)
+
+// String 返回顺序保证的可读名称。
+func (o Ordering) String() string {
+	switch o {
+	case OrderUnordered:
+		return "unordered"
+	case OrderFIFO:
+		return "fifo"
+	case OrderFIFOPerKey:
+		return "fifo-per-key"
+	default:
+		return "unknown"
+	}
+}
+
+// WithOrdering 显式指定池的执行顺序保证，默认为 OrderUnordered。
+//
+// OrderFIFOPerKey 按键的哈希值把任务固定分派给某一个 worker（一个 worker 负责多个键），
+// 因此慢任务会推迟分到同一个 worker 的其他键，但不影响其他 worker 上的键：
+// worker 忙碌时，分派给它的任务暂存在分派方，暂存总数达到队列容量后分派方才停止从队列取任务。
+// worker 数在运行期间不再调整（见 SetWorkers）。
+// OrderFIFO 下 SetWorkers 只接受 1。
+//
+// 会打乱顺序的配置与 OrderFIFO、OrderFIFOPerKey 不能同时使用，New 会直接 panic 而不是悄悄失去保证：
+// 同步模式、WithQueue、WithOverflowPool、WithInlineThreshold、WithCoalesceWindow、WithHardTimeout、WithRetryRequeue，
+// 以及显式设置的 ReentrantOverflow 与 ReentrantCallerRuns（未设置时重入策略默认为 ReentrantError）；
+// OrderFIFO 还要求 worker 数为 1，且不能启用 WithGOMAXPROCSTracking。
+func WithOrdering(o Ordering) Option {
+	return func(opts *Options) {
+		opts.ordering = o
+	}
+}
+
+// checkOrdering 检查 workerNum 与配置能否提供所要求的顺序保证，不能时返回说明冲突的错误。
+// 有序的池未显式设置重入策略时，默认的 ReentrantOverflow 换成 ReentrantError。
+func (o *Options) checkOrdering(workerNum int) error {
+	if o.ordering == OrderUnordered {
+		return nil
+	}
+	if !o.reentrantSet {
+		o.reentrant = ReentrantError
+	}
+	var conflict string
+	switch {
+	case o.ordering != OrderFIFO && o.ordering != OrderFIFOPerKey:
+		return fmt.Errorf("gopoolx: unknown ordering %d", o.ordering)
+	case o.ordering == OrderFIFO && workerNum != 1:
+		conflict = fmt.Sprintf("%d workers", workerNum)
+	case o.ordering == OrderFIFO && o.procsMultiplier > 0:
+		conflict = "WithGOMAXPROCSTracking"
+	case o.synchronous:
+		conflict = "WithSynchronous"
+	case o.queue != nil:
+		conflict = "WithQueue"
+	case o.overflowPool != nil:
+		conflict = "WithOverflowPool"
+	case o.inlineThreshold > 0:
+		conflict = "WithInlineThreshold"
+	case o.coalesceWindow > 0:
+		conflict = "WithCoalesceWindow"
+	case o.hardTimeout > 0:
+		conflict = "WithHardTimeout"
+	case o.retryRequeue:
+		conflict = "WithRetryRequeue"
+	case o.reentrant == ReentrantOverflow:
+		conflict = "ReentrantOverflow"
+	case o.reentrant == ReentrantCallerRuns:
+		conflict = "ReentrantCallerRuns"
+	default:
+		return nil
+	}
+	return fmt.Errorf("gopoolx: %v ordering cannot be preserved with %s", o.ordering, conflict)
+}
+
+// dispatched 判断池是否通过分派 goroutine 把任务交给各 worker，见 WithDispatchMode。
+// OrderFIFOPerKey 依赖分派把同一个键的任务交给同一个 worker。
+func (p *Pool) dispatched() bool {
+	return p.opts.dispatchMode != DispatchShared || p.opts.ordering == OrderFIFOPerKey
+}
+
+// keyedWorker 在 OrderFIFOPerKey 下返回负责 j 的键的 worker，未带键或不要求按键有序时返回 nil。
+func (p *Pool) keyedWorker(workers []*dispatchWorker, j *job) *dispatchWorker {
+	if p.opts.ordering != OrderFIFOPerKey || j.key == "" {
+		return nil
+	}
+	return workers[maphash.String(p.keySeed, j.key)%uint64(len(workers))]
+}
@@ -0,0 +1,64 @@
+package gopoolx
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestOrderFIFOPerKeySlowKey 一个键的慢任务及其积压不应推迟分到其他 worker 的键，
+// 同时积压的任务仍按提交顺序执行。
+func TestOrderFIFOPerKeySlowKey(t *testing.T) {
+	const workers = 4
+	p := newRunningPool(t, workers, WithOrdering(OrderFIFOPerKey))
+	slot := func(key string) uint64 { return maphash.String(p.keySeed, key) % workers }
+	slow, fast := "slow", ""
+	for i := 0; fast == ""; i++ {
+		if k := fmt.Sprintf("fast-%d", i); slot(k) != slot(slow) {
+			fast = k
+		}
+	}
+
+	release := make(chan struct{})
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	p.Submit(func(context.Context) error {
+		<-release
+		return nil
+	}, WithKey(slow))
+	for i := 0; i < 5; i++ {
+		p.Submit(func(context.Context) error {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			return nil
+		}, WithKey(slow))
+	}
+
+	done := make(chan struct{})
+	p.Submit(func(context.Context) error {
+		close(done)
+		return nil
+	}, WithKey(fast))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a slow key delayed a key on another worker")
+	}
+
+	close(release)
+	p.Wait()
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("slow key ran in order %v, want submission order", order)
+		}
+	}
+	if len(order) != 5 {
+		t.Fatalf("slow key ran %d queued tasks, want 5", len(order))
+	}
+}
@@ -0,0 +1,30 @@
+package gopoolx
+
+import "context"
+
+// WithOverflowPool 设置溢出池：队列已满时，任务不再按队列满策略等待或丢弃，
+// 而是转交给 other 的 worker 执行，例如一个规模更小、优先级更低的后备池。
+// 转交的任务仍属于当前池：重试、熔断等按当前池的配置进行，错误计入当前池的 Errors，
+// 当前池的 Wait 也会等待它们；other 只提供执行的 worker，并按它自己的队列满策略接收任务。
+// other 同样无法接收时，任务回到当前池的队列满策略处理。转交的任务数计入 Stats().Spilled。
+// other 需由调用方单独 Run，且不能是当前池本身。
+func WithOverflowPool(other *Pool) Option {
+	return func(o *Options) {
+		o.overflowPool = other
+	}
+}
+
+// spill 将已登记计数的任务转交给溢出池执行，返回是否转交成功。
+// 溢出池中执行的是一个包装任务，它在溢出池的 worker 中按当前池的配置执行原任务。
+func (p *Pool) spill(j *job) bool {
+	w := newJob(func(ctx context.Context) error {
+		p.exec(ctx, j)
+		return nil
+	}, nil)
+	if err := p.opts.overflowPool.submit(w); err != nil {
+		return false
+	}
+	p.addQueued(-1)
+	p.stats.spilled.Add(1)
+	return true
+}
@@ -1,9 +1,56 @@
 package gopoolx
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
 
-// panicError 将任务中的 panic 包装为 error，方便统一处理。
-// 目前主要用于 SubmitWithResult 中，将 panic 传递给 Future。
+// PanicError 是任务 panic 被恢复后得到的错误，携带 panic 的值与发生时的调用栈。
+// 调用方可以通过 errors.As(err, &pe) 取出，并根据 panic 的内容决定如何处理。
+type PanicError struct {
+	// Value 是 recover 得到的原始值
+	Value any
+	// Stack 是 panic 发生时的 goroutine 调用栈
+	Stack []byte
+}
+
+// Error 实现 error，返回 "task panic: <value>"。
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panic: %v", e.Value)
+}
+
+// Unwrap 在 panic 的值本身是 error 时返回它，使 errors.Is / errors.As 可以继续匹配。
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// panicError 将任务中的 panic 包装为 *PanicError，需在 recover 所在的 defer 中调用，
+// 以便捕获 panic 发生处的调用栈。
 func panicError(r any) error {
-	return fmt.Errorf("task panic: %v", r)
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
+
+// isPanic 判断 err 是否来自被恢复的 panic。
+func isPanic(err error) bool {
+	var pe *PanicError
+	return errors.As(err, &pe)
+}
+
+// recoverAttempt 执行任务 j 的一次尝试。WithRetryOnPanic(true) 且使用内置恢复时在这一次尝试内恢复 panic，
+// 使它像普通错误一样交给 retryLoop 决定是否重试，而不是越过重试循环直接结束任务。
+func (p *Pool) recoverAttempt(ctx context.Context, j *job) (err error) {
+	if !p.opts.retryOnPanic || p.opts.customRecovery {
+		return p.runAttempt(ctx, j)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r)
+		}
+	}()
+	return p.runAttempt(ctx, j)
 }
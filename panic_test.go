@@ -0,0 +1,45 @@
+package gopoolx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryOnPanicResultTask(t *testing.T) {
+	p := newRunningPool(t, 1, WithRetry(2), WithRetryOnPanic(true))
+	var calls atomic.Int32
+	f := SubmitWithResult(p, func(context.Context) (int, error) {
+		if calls.Add(1) < 3 {
+			panic("boom")
+		}
+		return 42, nil
+	})
+	v, err := f.GetTimeout(time.Second)
+	if err != nil || v != 42 {
+		t.Fatalf("got (%d, %v), want (42, nil)", v, err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+	p.Wait()
+	if s := p.Stats(); s.Succeeded != 1 || s.Retries != 2 {
+		t.Fatalf("Succeeded = %d, Retries = %d, want 1 and 2", s.Succeeded, s.Retries)
+	}
+}
+
+func TestResultTaskPanicNotRetriedByDefault(t *testing.T) {
+	p := newRunningPool(t, 1, WithRetry(2))
+	var calls atomic.Int32
+	f := SubmitWithResult(p, func(context.Context) (int, error) {
+		calls.Add(1)
+		panic("boom")
+	})
+	if _, err := f.GetTimeout(time.Second); !isPanic(err) {
+		t.Fatalf("got %v, want *PanicError", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,414 @@
+package gopoolx
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrNoPersistentQueue 表示调用 SubmitPersistent 的池没有通过 WithPersistentQueue 启用持久化队列。
+var ErrNoPersistentQueue = errors.New("persistent queue is not configured")
+
+// TaskDecoder 将持久化的任务数据还原为可执行的 Task。
+// 任务数据的编码方式由调用方决定（JSON、protobuf 等），池只负责原样保存。
+type TaskDecoder func(payload []byte) (Task, error)
+
+// WithPersistentQueue 启用基于磁盘的持久化队列：通过 SubmitPersistent 提交的任务数据
+// 在入队前写入 dir 下的预写日志（WAL）并落盘，任务结束（成功或重试耗尽后失败）后才被确认。
+// 进程重启后再次创建同样配置的池时，尚未确认的任务会在 Run 时由 decode 还原并重新提交，
+// 使池成为一个轻量的持久化任务执行器。
+//
+// 语义为至少一次：进程在任务执行结束、确认落盘之前退出时，任务会在重启后再次执行，
+// 任务需要是幂等的。日志按段存储，段内任务全部确认后旧段会被删除；打开队列时会压缩日志。
+// 打开目录失败时，错误会由 SubmitPersistent 返回并在 Run 时计入 Errors。
+func WithPersistentQueue(dir string, decode TaskDecoder) Option {
+	return func(o *Options) {
+		o.persistDir = dir
+		o.persistDecode = decode
+	}
+}
+
+// SubmitPersistent 持久化任务数据 payload 后将其提交到池中，
+// payload 经 WithPersistentQueue 指定的 decode 还原为任务执行。
+// 数据落盘后才会入队；提交失败（例如队列满被拒绝、任务被丢弃）的数据会被立即确认，不会在重启后重放。
+func (p *Pool) SubmitPersistent(payload []byte, opts ...SubmitOption) error {
+	s := p.store
+	if s == nil {
+		return ErrNoPersistentQueue
+	}
+	if s.err != nil {
+		return s.err
+	}
+	task, err := p.opts.persistDecode(payload)
+	if err != nil {
+		return err
+	}
+	id, err := s.append(payload)
+	if err != nil {
+		return err
+	}
+	return p.submitStored(id, task, opts)
+}
+
+// submitStored 提交一个已持久化的任务，任务结束或提交失败时确认对应的日志记录。
+func (p *Pool) submitStored(id uint64, task Task, opts []SubmitOption) error {
+	j := newJob(task, opts)
+	j.after = func(error) {
+		p.ackStored(id)
+	}
+	err := p.submit(j)
+	if err != nil {
+		p.ackStored(id)
+	}
+	if err == ErrDiscarded {
+		return nil
+	}
+	return err
+}
+
+// ackStored 确认日志记录，确认失败的错误计入 Errors。
+func (p *Pool) ackStored(id uint64) {
+	if err := p.store.ack(id); err != nil {
+		p.errs.Add(err)
+	}
+}
+
+// replayStored 重新提交打开队列时日志中尚未确认的任务，由首次 Run 在后台调用。
+func (p *Pool) replayStored() {
+	defer p.inflight.done(1)
+
+	if p.store.err != nil {
+		p.errs.Add(p.store.err)
+		return
+	}
+	for _, rec := range p.store.takeRecovered() {
+		task, err := p.opts.persistDecode(rec.payload)
+		if err != nil {
+			// 无法还原的数据会一直无法执行，确认后丢弃，避免每次重启都失败
+			p.errs.Add(fmt.Errorf("persistent task %d: %w", rec.id, err))
+			p.ackStored(rec.id)
+			continue
+		}
+		if err := p.submitStored(rec.id, task, nil); err != nil {
+			p.errs.Add(fmt.Errorf("persistent task %d: %w", rec.id, err))
+		}
+	}
+}
+
+// WAL 记录类型。
+const (
+	walEnqueue byte = 1
+	walAck     byte = 2
+)
+
+// walSegmentLimit 是单个日志段的大小上限，超过后切换到新段。
+const walSegmentLimit = 16 << 20
+
+// walRecord 是一条尚未确认的入队记录。
+type walRecord struct {
+	id      uint64
+	payload []byte
+}
+
+// walStore 是持久化队列的日志存储。
+//
+// 每个段是一个追加写入的文件（wal-<序号>.log），记录格式为：
+//
+//	kind(1) | id(8) | len(4) | payload(len) | crc32(4)
+//
+// crc 覆盖 kind 到 payload 的全部字节。读取时遇到截断或校验失败的记录即停止读取该段。
+// 确认记录可能写在比入队记录更新的段中，因此只有当某个段及其之前的所有段都没有
+// 未确认的入队记录时，才会删除这些段。
+type walStore struct {
+	dir string
+	// err 是打开存储时的错误，非 nil 时存储不可用
+	err error
+
+	mu     sync.Mutex
+	nextID uint64
+	// segs 是现存的段序号，升序排列；最后一个是正在写入的段
+	segs []uint64
+	// live 记录每个段中尚未确认的入队记录数
+	live map[uint64]int
+	// owner 记录每个未确认记录所在的段
+	owner  map[uint64]uint64
+	active *os.File
+	w      *bufio.Writer
+	size   int64
+	// recovered 是打开时恢复的未确认记录，由首次 Run 取走重放
+	recovered []walRecord
+}
+
+// openWALStore 打开（必要时创建）dir 下的日志，恢复未确认的记录并压缩日志：
+// 未确认的记录被重写到一个新段中，旧段随后被删除。
+func openWALStore(dir string) *walStore {
+	s := &walStore{
+		dir:   dir,
+		live:  make(map[uint64]int),
+		owner: make(map[uint64]uint64),
+	}
+	if err := s.open(); err != nil {
+		s.err = fmt.Errorf("open persistent queue %s: %w", dir, err)
+	}
+	return s
+}
+
+// open 是 openWALStore 的实现。
+func (s *walStore) open() error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	old, err := s.listSegments()
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[uint64][]byte)
+	for _, seg := range old {
+		if err := s.readSegment(seg, pending); err != nil {
+			return err
+		}
+	}
+
+	next := uint64(1)
+	if len(old) > 0 {
+		next = old[len(old)-1] + 1
+	}
+	if err := s.openSegment(next); err != nil {
+		return err
+	}
+
+	ids := make([]uint64, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		if err := s.writeRecord(walEnqueue, id, pending[id]); err != nil {
+			return err
+		}
+		s.live[next]++
+		s.owner[id] = next
+		s.recovered = append(s.recovered, walRecord{id: id, payload: pending[id]})
+	}
+	if err := s.sync(); err != nil {
+		return err
+	}
+
+	// 压缩后的新段已完整落盘，旧段可以删除
+	for _, seg := range old {
+		if err := os.Remove(s.segmentPath(seg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSegments 返回目录中现存的段序号，升序排列。
+func (s *walStore) listSegments() ([]uint64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var segs []uint64
+	for _, e := range entries {
+		var seg uint64
+		name := e.Name()
+		if !strings.HasPrefix(name, "wal-") || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		if _, err := fmt.Sscanf(name, "wal-%d.log", &seg); err == nil {
+			segs = append(segs, seg)
+		}
+	}
+	slices.Sort(segs)
+	return segs, nil
+}
+
+// readSegment 读取一个段，将入队记录加入 pending、将确认记录从 pending 中移除，
+// 并推进 nextID。
+func (s *walStore) readSegment(seg uint64, pending map[uint64][]byte) error {
+	f, err := os.Open(s.segmentPath(seg))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var header [13]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			// 正常结束或尾部记录被截断
+			return nil
+		}
+		kind := header[0]
+		id := binary.BigEndian.Uint64(header[1:9])
+		n := binary.BigEndian.Uint32(header[9:13])
+		body := make([]byte, int(n)+4)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil
+		}
+		payload, sum := body[:n], binary.BigEndian.Uint32(body[n:])
+		crc := crc32.NewIEEE()
+		crc.Write(header[:])
+		crc.Write(payload)
+		if crc.Sum32() != sum {
+			return nil
+		}
+
+		switch kind {
+		case walEnqueue:
+			pending[id] = payload
+		case walAck:
+			delete(pending, id)
+		}
+		if id >= s.nextID {
+			s.nextID = id + 1
+		}
+	}
+}
+
+// segmentPath 返回段文件的路径。
+func (s *walStore) segmentPath(seg uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("wal-%08d.log", seg))
+}
+
+// openSegment 创建并切换到序号为 seg 的新段，调用方需持有 s.mu 或处于打开阶段。
+func (s *walStore) openSegment(seg uint64) error {
+	f, err := os.OpenFile(s.segmentPath(seg), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if s.active != nil {
+		if err := s.sync(); err != nil {
+			f.Close()
+			return err
+		}
+		s.active.Close()
+	}
+	s.active, s.w, s.size = f, bufio.NewWriter(f), 0
+	s.segs = append(s.segs, seg)
+	return nil
+}
+
+// writeRecord 将一条记录写入当前段的缓冲区。
+func (s *walStore) writeRecord(kind byte, id uint64, payload []byte) error {
+	var header [13]byte
+	header[0] = kind
+	binary.BigEndian.PutUint64(header[1:9], id)
+	binary.BigEndian.PutUint32(header[9:13], uint32(len(payload)))
+	crc := crc32.NewIEEE()
+	crc.Write(header[:])
+	crc.Write(payload)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+
+	for _, b := range [][]byte{header[:], payload, sum[:]} {
+		if _, err := s.w.Write(b); err != nil {
+			return err
+		}
+	}
+	s.size += int64(len(header) + len(payload) + len(sum))
+	return nil
+}
+
+// sync 将缓冲区写入文件并落盘。
+func (s *walStore) sync() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.active.Sync()
+}
+
+// append 持久化一条入队记录并落盘，返回记录编号。
+func (s *walStore) append(payload []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil {
+		return 0, ErrPoolClosed
+	}
+
+	if s.size >= walSegmentLimit {
+		if err := s.openSegment(s.segs[len(s.segs)-1] + 1); err != nil {
+			return 0, err
+		}
+	}
+	id := s.nextID
+	s.nextID++
+	if err := s.writeRecord(walEnqueue, id, payload); err != nil {
+		return 0, err
+	}
+	if err := s.sync(); err != nil {
+		return 0, err
+	}
+	seg := s.segs[len(s.segs)-1]
+	s.live[seg]++
+	s.owner[id] = seg
+	return id, nil
+}
+
+// ack 写入确认记录，并删除已不再需要的旧段。
+// 确认记录只写入缓冲区而不单独落盘：丢失一条确认最多导致任务在重启后多执行一次。
+func (s *walStore) ack(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seg, ok := s.owner[id]
+	if !ok || s.active == nil {
+		return nil
+	}
+	delete(s.owner, id)
+	s.live[seg]--
+	if err := s.writeRecord(walAck, id, nil); err != nil {
+		return err
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.removeDrained()
+}
+
+// removeDrained 从最旧的段开始，删除所有已无未确认记录的非活动段。
+func (s *walStore) removeDrained() error {
+	for len(s.segs) > 1 && s.live[s.segs[0]] == 0 {
+		seg := s.segs[0]
+		if err := os.Remove(s.segmentPath(seg)); err != nil {
+			return err
+		}
+		delete(s.live, seg)
+		s.segs = s.segs[1:]
+	}
+	return nil
+}
+
+// takeRecovered 取走打开时恢复的未确认记录，只有第一次调用会返回数据。
+func (s *walStore) takeRecovered() []walRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recs := s.recovered
+	s.recovered = nil
+	return recs
+}
+
+// close 将缓冲的确认记录落盘并关闭当前段，由 Wait 在池关闭时调用。
+func (s *walStore) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil {
+		return nil
+	}
+	err := s.sync()
+	if cerr := s.active.Close(); err == nil {
+		err = cerr
+	}
+	s.active = nil
+	return err
+}
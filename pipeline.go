@@ -0,0 +1,180 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+)
+
+// Pipeline 管理由多个 Stage 组成的流水线：统一的取消、错误传播与等待。
+// 典型用法（每个阶段使用各自的池，避免阶段之间互相占用 worker）：
+//
+//	pl := gopoolx.NewPipeline(ctx)
+//	ids := gopoolx.Source(pl, 1, 2, 3)
+//	users := gopoolx.Pipe(pl, ids, gopoolx.NewStage(fetchPool, fetchUser))
+//	names := gopoolx.Pipe(pl, users, gopoolx.NewStage(renderPool, renderName))
+//	result, err := gopoolx.Collect(pl, names)
+//
+// 任一阶段的任务在重试耗尽后仍失败时，整条流水线会被取消，
+// 尚未开始的任务不再执行，Wait / Collect 返回第一个错误。
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wg 等待流水线中所有后台 goroutine 与任务结束
+	wg sync.WaitGroup
+
+	errOnce sync.Once
+	// err 是流水线中出现的第一个错误
+	err error
+}
+
+// NewPipeline 创建一条流水线，ctx 结束时整条流水线被取消。
+func NewPipeline(ctx context.Context) *Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context 返回流水线的上下文，它在流水线失败或被取消时结束。
+func (pl *Pipeline) Context() context.Context {
+	return pl.ctx
+}
+
+// Wait 等待流水线中所有阶段结束，返回第一个错误；
+// 流水线未失败但 ctx 被外部取消时返回 ctx.Err()。
+func (pl *Pipeline) Wait() error {
+	pl.wg.Wait()
+	// 先读取 ctx 状态再释放资源，避免把 cancel 误判为外部取消
+	ctxErr := pl.ctx.Err()
+	pl.cancel()
+
+	pl.fail(ctxErr)
+	return pl.err
+}
+
+// fail 记录第一个错误并取消整条流水线，err 为 nil 时忽略。
+func (pl *Pipeline) fail(err error) {
+	if err == nil {
+		return
+	}
+	pl.errOnce.Do(func() {
+		pl.err = err
+		pl.cancel()
+	})
+}
+
+// Stage 是流水线中的一个处理阶段：使用 pool 并发地将 T 转换为 U。
+type Stage[T, U any] struct {
+	pool *Pool
+	fn   func(ctx context.Context, in T) (U, error)
+	// buffer 是输出通道的缓冲大小
+	buffer int
+}
+
+// NewStage 创建一个在 pool 上执行 fn 的流水线阶段。
+func NewStage[T, U any](pool *Pool, fn func(ctx context.Context, in T) (U, error)) *Stage[T, U] {
+	return &Stage[T, U]{
+		pool: pool,
+		fn:   fn,
+	}
+}
+
+// WithBuffer 设置阶段输出通道的缓冲大小，默认无缓冲。
+// 缓冲越大，上下游之间允许堆积的结果越多。返回 s 本身以便链式调用。
+func (s *Stage[T, U]) WithBuffer(n int) *Stage[T, U] {
+	s.buffer = n
+	return s
+}
+
+// Source 将 items 作为流水线的输入源，返回依次产出 items 的通道。
+func Source[T any](pl *Pipeline, items ...T) <-chan T {
+	out := make(chan T)
+	pl.wg.Add(1)
+	go func() {
+		defer pl.wg.Done()
+		defer close(out)
+		for _, item := range items {
+			select {
+			case out <- item:
+			case <-pl.ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Pipe 将输入通道 in 接入阶段 s，返回该阶段的输出通道。
+// 每个输入都会作为一个任务提交到阶段的池中执行，输出顺序不保证与输入一致；
+// 下游消费变慢时，worker 会阻塞在发送结果上，进而让 Submit 阻塞，形成背压。
+// 输入耗尽且所有任务结束（或流水线被取消）后，输出通道会被关闭。
+func Pipe[T, U any](pl *Pipeline, in <-chan T, s *Stage[T, U]) <-chan U {
+	out := make(chan U, s.buffer)
+
+	pl.wg.Add(1)
+	go func() {
+		defer pl.wg.Done()
+
+		// inflight 等待本阶段已提交的任务结束后再关闭输出通道
+		var inflight sync.WaitGroup
+		defer func() {
+			inflight.Wait()
+			close(out)
+		}()
+
+		for {
+			var (
+				item T
+				ok   bool
+			)
+			select {
+			case item, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-pl.ctx.Done():
+				return
+			}
+
+			inflight.Add(1)
+			j := newJob(func(wctx context.Context) error {
+				// 流水线已取消时直接跳过，不再占用下游
+				if pl.ctx.Err() != nil {
+					return nil
+				}
+				ctx, cancel := mergeContext(wctx, pl.ctx)
+				defer cancel()
+
+				res, err := s.fn(ctx, item)
+				if err != nil {
+					return err
+				}
+				select {
+				case out <- res:
+				case <-pl.ctx.Done():
+				}
+				return nil
+			}, nil)
+			j.after = func(err error) {
+				pl.fail(err)
+				inflight.Done()
+			}
+			if err := s.pool.submit(j); err != nil {
+				pl.fail(err)
+				inflight.Done()
+			}
+		}
+	}()
+	return out
+}
+
+// Collect 消费通道 in 中的全部结果（扇入），并等待整条流水线结束。
+// 返回收集到的结果以及 Wait 的返回值。
+func Collect[T any](pl *Pipeline, in <-chan T) ([]T, error) {
+	var res []T
+	for v := range in {
+		res = append(res, v)
+	}
+	return res, pl.Wait()
+}
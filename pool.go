@@ -2,7 +2,11 @@ package gopoolx
 
 import (
 	"context"
+	"hash/maphash"
+	"iter"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,10 +19,33 @@ import (
 type Pool struct {
 	// workerNum 是并发执行任务的 worker 数量
 	workerNum int
+	// options 是创建池时传入的配置，供 CloneWith 复用
+	options []Option
+	// inline 是内联执行的状态，未启用 WithInlineThreshold 时为 nil
+	inline *inlineExec
+	// spanAttrs 是写入每个 span 的池名称与标签，见 SpanAttributer
+	spanAttrs []Attr
+	// memory 是基于内存的准入控制，未启用 WithMemoryPressurePolicy 时为 nil
+	memory *memoryGuard
+	// coalesce 记录按键合并的窗口，未启用 WithCoalesceWindow 时为 nil
+	coalesce *coalescer
+	// startedAt / closedAt 是池开始运行与被关闭的时间，见 Report
+	startedAt atomic.Pointer[time.Time]
+	closedAt  atomic.Pointer[time.Time]
+	// completions 统计已结束的任务数，供 WaitN 等待
+	completions completions
+	// keySeed 是 OrderFIFOPerKey 下把键映射到 worker 的哈希种子
+	keySeed maphash.Seed
+	// size 是当前的 worker 数，可通过 SetWorkers 或 WithGOMAXPROCSTracking 调整
+	size atomic.Int64
+	// retire 通知一个 worker 退出，resizeReq 向 resizeWorkers 传递最新的目标 worker 数
+	retire    chan struct{}
+	resizeReq chan int
+	resizeMu  sync.Mutex
 	// tasks 是任务队列，worker 会从该通道中取出任务执行
-	tasks chan Task
-	// wg 用于等待所有提交的任务执行完成
-	wg sync.WaitGroup
+	tasks chan *job
+	// inflight 统计已提交但尚未结束的任务，Wait 据此等待并关闭池
+	inflight inflight
 	// once 用于确保任务通道只会被关闭一次，避免多次 Wait 调用导致 panic
 	once sync.Once
 
@@ -26,8 +53,71 @@ type Pool struct {
 	opts *Options
 	// errs 收集所有执行失败的任务错误
 	errs *ErrorCollector
+	// breaker 是可选的熔断器，未启用时为 nil
+	breaker *circuitBreaker
+	// budget 是可选的重试预算，未启用 WithRetryBudget 时为 nil
+	budget *retryBudget
+	// pending 是在途任务（排队中 + 执行中）的信号量，未启用 WithMaxPending 时为 nil
+	pending chan struct{}
+	// nextID 用于为任务句柄分配唯一编号
+	nextID atomic.Uint64
+	// registry 记录尚未结束的任务句柄
+	registry handleRegistry
+	// stats 保存运行统计，见 Stats
+	stats poolStats
+	// queued 是已入队（或正阻塞等待入队）但尚未被 worker 取出的任务数
+	queued atomic.Int64
+	// running 是 worker 正在执行的任务数
+	running atomic.Int64
+	// workerCtxs 记录 worker goroutine 编号到其 ctx 的映射，用于识别 worker 中的提交
+	workerCtxs sync.Map
+	// overflow 保存 worker 中提交、因队列已满而溢出的任务，见 ReentrantOverflow
+	overflow overflowList
+
+	// parent 是通过 Child 创建子池时的父池，顶层池为 nil
+	parent *Pool
+	// shares 是执行名额信号量，只有创建过子池的池才有，见 Child
+	shares     atomic.Pointer[chan struct{}]
+	sharesOnce sync.Once
+
+	// store 是持久化队列的日志存储，未启用 WithPersistentQueue 时为 nil
+	store *walStore
+	// replayOnce 保证日志中未确认的任务只在首次 Run 时重放一次
+	replayOnce sync.Once
+
+	// batchMu 在启用 WithAtomicBatch 时保护非阻塞入队与 SubmitAll 的整批预留：
+	// 单个提交持有读锁，整批提交持有写锁
+	batchMu sync.RWMutex
+
+	// delayed 保存通过 SubmitAfter / SubmitAt 提交、尚未到期的任务
+	delayed *delayQueue
+
+	// quit 在 Run 的 ctx 结束时关闭，通知后台 goroutine（如周期任务）退出
+	quit     chan struct{}
+	quitOnce sync.Once
+	// closed 在 Wait 关闭任务通道时一并关闭
+	closed chan struct{}
+	// hooks 是通过 OnShutdown 注册的关闭回调
+	hooks shutdownHooks
+	// killMu 保护 kill 与 killed：kill 取消 Run 派生的 ctx，killed 表示已调用 Kill，见 Kill
+	killMu sync.Mutex
+	kill   context.CancelCauseFunc
+	killed bool
+	// tune 保存可在运行期间修改的配置项，见 SetRetry 等
+	tune tunables
+	// results 向 ResultsSeq 的订阅方分发任务结果
+	results resultHub
+	// tags 按标签记录任务结果，见 WithTags
+	tags tagIndex
+	// plain 表示池的配置允许走 executeOnce 快速路径（仍需在执行时检查可运行期修改的重试与限流）
+	plain bool
+	// warmupOnce 保证 Warmup 只启动一次 worker，ready 表示已通过 Warmup 的探测
+	warmupOnce sync.Once
+	ready      atomic.Bool
 }
 
+var _ Submitter = (*Pool)(nil)
+
 // New 创建一个新的 Pool。
 //   - workerNum: worker 的数量（应为正数）
 //   - opts: 可选配置，例如重试次数、队列大小等
@@ -36,52 +126,275 @@ func New(workerNum int, opts ...Option) *Pool {
 	for _, opt := range opts {
 		opt(o)
 	}
+	if err := o.checkOrdering(workerNum); err != nil {
+		panic(err)
+	}
 
-	var ch chan Task
-	if o.queueSize > 0 {
-		ch = make(chan Task, o.queueSize)
+	var ch chan *job
+	if o.queueSize > 0 && o.queue == nil {
+		ch = make(chan *job, o.queueSize)
 	} else {
-		ch = make(chan Task)
+		ch = make(chan *job)
 	}
 
-	return &Pool{
+	p := &Pool{
 		workerNum: workerNum,
+		options:   slices.Clone(opts),
 		tasks:     ch,
 		opts:      o,
-		errs:      &ErrorCollector{},
+		errs:      newErrorCollector(o),
+		delayed:   newDelayQueue(),
+		quit:      make(chan struct{}),
+		closed:    make(chan struct{}),
+	}
+	p.memory = newMemoryGuard(o)
+	p.coalesce = newCoalescer(o)
+	if o.ordering == OrderFIFOPerKey {
+		p.keySeed = maphash.MakeSeed()
+	}
+	if o.inlineThreshold > 0 {
+		p.inline = newInlineExec(o.inlineThreshold)
+	}
+	p.breaker = newCircuitBreaker(o)
+	if o.retryBudget > 0 {
+		p.budget = newRetryBudget(o.retryBudget, o.clock)
+	}
+	if o.maxPending > 0 {
+		p.pending = make(chan struct{}, o.maxPending)
+	}
+	p.size.Store(int64(workerNum))
+	if o.procsMultiplier > 0 {
+		p.size.Store(int64(procsWorkers(o.procsMultiplier)))
+	}
+	p.retire = make(chan struct{})
+	p.resizeReq = make(chan int, 1)
+	p.plain = p.breaker == nil && o.retryBudget <= 0 && len(o.laneLimiters) == 0 &&
+		o.hardTimeout <= 0 && o.tracer == nil && !o.customRecovery
+	p.labelMetrics()
+	p.spanAttrs = p.identityAttrs()
+	p.guardHooks()
+	if o.synchronous {
+		p.markStarted()
+	}
+	p.inflight.init()
+	p.tune.init(o)
+	if o.persistDir != "" {
+		p.store = openWALStore(o.persistDir)
+	}
+	p.registry.historySize = o.taskHistory
+	// 内置令牌桶在选项解析时创建，此处统一切换到配置的时钟
+	for _, l := range o.laneLimiters {
+		if tb, ok := l.(*tokenBucket); ok {
+			tb.setClock(o.clock)
+		}
 	}
+	if tb, ok := o.limiter.(*tokenBucket); ok {
+		tb.setClock(o.clock)
+	}
+	if o.latencyBuckets != nil {
+		p.stats.queueWait = newHistogram(o.latencyBuckets)
+		p.stats.exec = newHistogram(o.latencyBuckets)
+	}
+	return p
+}
+
+// NewWithContext 创建一个与 ctx 绑定的池，语义类似结构化并发：池创建后立即以 ctx 启动 worker，
+// 无需再调用 Run；ctx 结束时停止接收新任务（提交返回 ErrPoolClosed），仍在排队的任务被跳过，
+// 正在执行的任务的 ctx 被取消，它们返回后 worker 全部退出，池随之自动关闭（等同于调用了 Wait）。
+// 因此只要任务响应 ctx，就不会有 worker goroutine 在 ctx 结束后继续存活。
+// ctx 结束前仍可调用 Wait 提前等待并关闭池。
+func NewWithContext(ctx context.Context, workerNum int, opts ...Option) *Pool {
+	p := New(workerNum, opts...)
+	p.Run(ctx)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Wait()
+		case <-p.closed:
+		}
+	}()
+	return p
 }
 
-// Submit 提交一个任务到池中，内部会递增 WaitGroup 计数。
+// Submit 提交一个任务到池中，内部会登记一个在途计数。
 // 根据配置的队列满策略，行为如下：
 //   - QueueFullWait: 队列满时阻塞等待，直到有空位再插入（默认）
 //   - QueueFullDiscard: 队列满时直接丢弃任务，不返回错误
-//   - QueueFullReturnError: 队列满时返回 ErrQueueFull 错误，任务计入失败
-func (p *Pool) Submit(task Task) error {
-	p.wg.Add(1)
+//   - QueueFullReturnError: 队列满时返回 *QueueFullError（包装了 ErrQueueFull），任务计入失败
+//
+// 若通过 WithMaxPending 限制了在途任务数，名额耗尽时同样按上述策略处理，
+// 返回错误模式下返回 ErrMaxPending。
+// Run 的 ctx 结束（worker 已退出）后提交返回 ErrPoolClosed，等待模式下阻塞中的提交同样返回。
+// 池已被 Wait 关闭时返回 ErrPoolClosed。
+//
+// opts 为单次提交的可选配置，例如 WithLane。
+func (p *Pool) Submit(task Task, opts ...SubmitOption) error {
+	if err := p.submit(acquireJob(task, opts)); err != ErrDiscarded {
+		return err
+	}
+	return nil
+}
+
+// submit 是提交任务的内部实现。
+// 与 Submit 不同，任务被丢弃时会返回 ErrDiscarded，
+// 便于 SubmitWithResult 等上层封装感知任务不会被执行。
+func (p *Pool) submit(j *job) error {
+	p.track(j)
+	if err := p.admit(j); err != nil {
+		p.settleRejected(j, err)
+		return err
+	}
+	if p.coalesce != nil && j.key != "" {
+		return p.coalesceSubmit(j)
+	}
+	return p.enqueue(j)
+}
+
+// track 为即将提交的任务补齐池级配置要求的状态：启用 WithTaskTracking 时为尚无句柄的任务创建句柄，
+// 启用 WithAutoIdempotencyKeys 时生成幂等键。所有提交入口（Submit、SubmitAll、SubmitAt 等）都经过这里。
+func (p *Pool) track(j *job) {
+	if p.opts.trackTasks && j.handle == nil {
+		newTaskHandle(p, j)
+	}
+	p.assignIdempotencyKey(j)
+}
+
+// settleRejected 将未被接受（提交失败或被丢弃）的任务句柄置为结束状态，并以 err 否认任务（见 WithAck）。
+// 返回 false 表示任务此前已被取消，其计数已由取消方释放。
+func (p *Pool) settleRejected(j *job, err error) bool {
+	if j.handle != nil {
+		status, herr := TaskFailed, err
+		if err == ErrDiscarded {
+			status, herr = TaskDiscarded, nil
+		}
+		if !j.handle.settleIfQueued(status, herr) {
+			return false
+		}
+	}
+	j.acknowledge(err)
+	return true
+}
+
+// reject 释放已登记计数但未能入队的任务：更新句柄状态并释放计数。
+// 任务若已被取消则不会重复释放。
+func (p *Pool) reject(j *job, err error) {
+	if p.settleRejected(j, err) {
+		p.done(j)
+	}
+}
+
+// submitAsync 异步提交任务，适用于在 worker（例如任务完成回调）中派生新任务的场景。
+// 它先同步预留一个在途计数，保证 Wait 不会在任务真正提交前返回，
+// 再在新的 goroutine 中提交，避免队列已满时阻塞当前 worker 造成死锁。
+// 提交失败时以对应错误调用 onErr（可为 nil）。
+func (p *Pool) submitAsync(j *job, onErr func(err error)) {
+	if err := p.inflight.add(1); err != nil {
+		p.settleRejected(j, err)
+		if onErr != nil {
+			onErr(err)
+		}
+		return
+	}
+	go func() {
+		defer p.inflight.done(1)
+		if err := p.submit(j); err != nil && onErr != nil {
+			onErr(err)
+		}
+	}()
+}
+
+// admit 为一个新提交的任务 j 登记计数：通过准入过滤、递增在途计数、通过内存准入、占用在途名额并登记其标签。
+// 被过滤器拒绝时返回过滤器的错误，池已关闭时返回 ErrPoolClosed；准入或占用名额失败时会撤销在途计数并返回对应错误。
+func (p *Pool) admit(j *job) error {
+	if err := p.filterAdmission(j); err != nil {
+		return err
+	}
+	if err := p.inflight.add(1); err != nil {
+		return err
+	}
+
+	// 未启用 WithMemoryPressurePolicy 与 WithMaxPending 时直接通过
+	if err := p.admitMemory(); err != nil {
+		p.inflight.done(1)
+		return err
+	}
+	if err := p.acquirePending(); err != nil {
+		p.inflight.done(1)
+		return err
+	}
+	if len(j.tags) > 0 {
+		p.tags.begin(j.tags)
+	}
+	p.register(j)
+	return nil
+}
+
+// enqueue 按队列满策略将已登记计数的任务放入队列。
+// 任务未能入队时会通过 reject 释放其计数。
+func (p *Pool) enqueue(j *job) error {
+	if p.opts.synchronous {
+		p.run(context.Background(), j)
+		return nil
+	}
+	// Run 的 ctx 结束后 worker 已退出，入队的任务不会再被执行
+	select {
+	case <-p.quit:
+		p.reject(j, ErrPoolClosed)
+		return ErrPoolClosed
+	default:
+	}
+	if p.opts.queue != nil {
+		return p.pushQueue(j)
+	}
+	// 策略只读取一次，运行期间通过 SetQueueFullPolicy 修改时，同一次提交内的行为保持一致
+	policy := p.queueFullPolicy()
+	defer p.lockForBatch(policy)()
+	return p.enqueueLocked(j, policy)
+}
 
-	switch p.opts.queueFullPolicy {
+// enqueueLocked 按策略 policy 将任务写入队列通道，是 enqueue 在完成关闭检查并取得 batchMu 之后的部分；
+// SubmitAll 的整批预留持有写锁后直接调用它。
+func (p *Pool) enqueueLocked(j *job, policy QueueFullPolicy) error {
+	p.markQueued(j)
+
+	// 配置了溢出池时，队列已满的任务优先转交给溢出池执行
+	if p.opts.overflowPool != nil {
+		select {
+		case p.tasks <- j:
+			return nil
+		default:
+		}
+		if p.spill(j) {
+			return nil
+		}
+	}
+
+	switch policy {
 	case QueueFullDiscard:
 		// 队列满时直接丢弃任务
 		select {
-		case p.tasks <- task:
-			// 正常入队，由 worker 负责执行并在结束时调用 wg.Done
+		case p.tasks <- j:
+			// 正常入队，由 worker 负责执行并在结束时调用 done
 		default:
-			// 队列已满：撤销之前的 Add，保持 WaitGroup 计数正确
-			p.wg.Done()
+			// 队列已满：撤销之前的 Add 与在途名额，保持计数正确
+			p.addQueued(-1)
+			p.reject(j, ErrDiscarded)
+			return ErrDiscarded
 		}
 		return nil
 
 	case QueueFullReturnError:
 		// 队列满时返回错误，任务计入失败
 		select {
-		case p.tasks <- task:
-			// 正常入队，由 worker 负责执行并在结束时调用 wg.Done
+		case p.tasks <- j:
+			// 正常入队，由 worker 负责执行并在结束时调用 done
 		default:
-			// 队列已满：撤销之前的 Add，将错误加入错误收集器，并返回错误
-			p.wg.Done()
-			p.errs.Add(ErrQueueFull)
-			return ErrQueueFull
+			// 队列已满：撤销之前的 Add 与在途名额，将错误加入错误收集器，并返回错误
+			p.addQueued(-1)
+			err := p.queueFullError(policy)
+			p.reject(j, err)
+			p.errs.Add(err)
+			return err
 		}
 		return nil
 
@@ -89,76 +402,649 @@ func (p *Pool) Submit(task Task) error {
 		fallthrough
 	default:
 		// 默认等待模式：在任务队列满时阻塞，直到有空间写入
-		p.tasks <- task
+		if p.inline != nil || p.opts.reentrant != ReentrantBlock {
+			select {
+			case p.tasks <- j:
+				return nil
+			default:
+			}
+			// 队列已满：近期任务都很短时直接在提交方执行，见 WithInlineThreshold
+			if p.tryInline(j) {
+				return nil
+			}
+			// 提交方若是本池的 worker，继续阻塞可能死锁
+			if p.opts.reentrant != ReentrantBlock {
+				if ctx, ok := p.workerContext(); ok {
+					return p.enqueueReentrant(ctx, j)
+				}
+			}
+		}
+		// Run 的 ctx 结束后 worker 不再取任务，继续阻塞将永远无法返回
+		select {
+		case p.tasks <- j:
+			return nil
+		case <-p.quit:
+			p.addQueued(-1)
+			p.reject(j, ErrPoolClosed)
+			return ErrPoolClosed
+		}
+	}
+}
+
+// queueFullError 按当前的队列状态构造队列已满错误。
+func (p *Pool) queueFullError(policy QueueFullPolicy) error {
+	return &QueueFullError{Len: len(p.tasks), Cap: cap(p.tasks), Policy: policy}
+}
+
+// markQueued 在任务即将入队时递增排队计数，并在需要时记录入队时间。
+func (p *Pool) markQueued(j *job) {
+	if p.opts.maxQueueAge > 0 || p.stats.queueWait != nil || p.opts.onTaskComplete != nil || p.opts.metrics != nil ||
+		p.opts.deadlinePropagation {
+		j.enqueuedAt = p.opts.clock.Now()
+	}
+	p.addQueued(1)
+}
+
+// lockForBatch 在启用 WithAtomicBatch 且为非阻塞策略时获取 batchMu 读锁，
+// 返回对应的释放函数；其他情况下返回空操作。
+func (p *Pool) lockForBatch(policy QueueFullPolicy) func() {
+	if !p.opts.atomicBatch || policy == QueueFullWait {
+		return func() {}
+	}
+	p.batchMu.RLock()
+	return p.batchMu.RUnlock
+}
+
+// acquirePending 占用一个在途任务名额，名额耗尽时按队列满策略处理。
+// 未启用 WithMaxPending 时直接返回 nil。
+func (p *Pool) acquirePending() error {
+	if p.pending == nil {
 		return nil
 	}
+	policy := p.queueFullPolicy()
+	defer p.lockForBatch(policy)()
+
+	switch policy {
+	case QueueFullDiscard:
+		select {
+		case p.pending <- struct{}{}:
+			return nil
+		default:
+			return ErrDiscarded
+		}
+
+	case QueueFullReturnError:
+		select {
+		case p.pending <- struct{}{}:
+			return nil
+		default:
+			p.errs.Add(ErrMaxPending)
+			return ErrMaxPending
+		}
+
+	default:
+		select {
+		case p.pending <- struct{}{}:
+			return nil
+		case <-p.quit:
+			return ErrPoolClosed
+		}
+	}
+}
+
+// done 标记一个已提交任务 j 结束：释放在途名额并递减在途计数与其标签的未结束计数。
+func (p *Pool) done(j *job) {
+	if len(j.tags) > 0 {
+		p.tags.end(j.tags)
+	}
+	if p.pending != nil {
+		<-p.pending
+	}
+	// 先于释放在途计数记录，Wait 返回时 WaitN 已能看到全部结束的任务
+	p.completions.add()
+	p.inflight.done(1)
 }
 
-// Run 启动指定数量的 worker。
-// ctx 结束时（超时、取消等），worker 会自动退出。
+// Run 启动指定数量的 worker，以及延迟任务的调度 goroutine。
+// ctx 结束时（超时、取消等），worker 会自动退出，尚未到期的延迟任务会被取消，
+// 仍在排队的任务以 ctx 的错误结束而不再执行（见 drain），因此 Wait 总能返回。
+// 同步模式（WithSynchronous）下 Run 不做任何事。
 func (p *Pool) Run(ctx context.Context) {
-	for i := 0; i < p.workerNum; i++ {
-		go p.worker(ctx)
+	if p.opts.synchronous {
+		return
+	}
+	p.markStarted()
+	ctx = p.killable(ctx)
+	// 所有任务的 ctx 都派生自这里，供 Checkpoint 找到所属的池，执行任务时无需再逐个附加
+	ctx = context.WithValue(ctx, poolKey{}, p)
+	if p.inline != nil {
+		p.inline.ctx.Store(&ctx)
+	}
+	if p.dispatched() {
+		p.runDispatched(ctx)
+	} else {
+		for i := 0; i < p.workers(); i++ {
+			go p.worker(ctx)
+		}
+		go p.resizeWorkers(ctx)
+	}
+	go p.runDelayed(ctx)
+	go p.watchQuit(ctx)
+	if p.opts.idleAfter > 0 && p.opts.onIdle != nil {
+		go p.watchIdle(ctx)
+	}
+	if p.opts.queue != nil {
+		go p.pumpQueue(ctx)
+	}
+	if p.store != nil {
+		p.replayOnce.Do(func() {
+			// 重放期间占用一个在途计数，保证 Wait 会等待重放的任务
+			if p.inflight.add(1) == nil {
+				go p.replayStored()
+			}
+		})
+	}
+}
+
+// watchQuit 在 ctx 结束时关闭 quit 通道并排空队列；池先被 Wait 关闭时直接退出。
+func (p *Pool) watchQuit(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		p.quitOnce.Do(func() {
+			close(p.quit)
+			p.drain(ctx.Err())
+		})
+	case <-p.closed:
+	}
+}
+
+// drain 在 Run 的 ctx 结束、worker 退出后，以 err 结束队列中剩余的任务：
+// 与出队时 ctx 已结束的任务一样置为 TaskCanceled、计入 Stats().Skipped 而不计入 Errors，
+// 并释放其计数，使 Wait 总能返回。此后的提交在入队前即返回 ErrPoolClosed；
+// 与 ctx 结束同时发生的提交仍可能入队，因此 drain 会持续排空，直到 Wait 关闭队列。
+// 溢出列表中的任务由提交它的 worker 在退出前跳过，无需在此处理。
+func (p *Pool) drain(err error) {
+	if q := p.opts.queue; q != nil {
+		for {
+			item, perr := q.Pop(context.Background())
+			if perr != nil {
+				return
+			}
+			p.addQueued(-1)
+			p.skip(item.j, TaskCanceled, err, &p.stats.skipped)
+		}
+	}
+	for j := range p.tasks {
+		p.addQueued(-1)
+		p.skip(j, TaskCanceled, err, &p.stats.skipped)
 	}
 }
 
-// worker 是实际执行 Task 的 worker 循环。
-// 它会根据 ctx 或任务通道关闭而退出。
+// worker 是实际执行 Task 的 worker goroutine，意外退出时会被新的 worker 替换（见 WithOnWorkerRestart）。
 func (p *Pool) worker(ctx context.Context) {
+	p.supervise(func() { p.workLoop(ctx) }, func() { go p.worker(ctx) })
+}
+
+// workLoop 是 worker 的任务循环，它会根据 ctx、任务通道关闭或 worker 数缩减（见 SetWorkers）而退出。
+// 启用 WithDequeueBatch 时，每次取到任务后会顺带取走队列中的若干任务，并在本地依次执行。
+func (p *Pool) workLoop(ctx context.Context) {
+	ctx = p.withWorkerBuffer(ctx)
+	defer p.startWorker(ctx)()
+
+	var batch []*job
+	if n := p.opts.dequeueBatch; n > 1 {
+		batch = make([]*job, 0, n)
+	}
 	for {
+		// 优先执行溢出任务：它们来自正在执行的任务，往往是其完成所依赖的子任务
+		if j := p.overflow.pop(); j != nil {
+			p.addQueued(-1)
+			p.work(ctx, j)
+			continue
+		}
 		select {
 		case <-ctx.Done():
 			return
-		case task, ok := <-p.tasks:
+		case <-p.retire:
+			return
+		case j, ok := <-p.tasks:
 			if !ok {
 				return
 			}
-			p.executeWithRetry(ctx, task)
-			p.wg.Done()
+			p.addQueued(-1)
+			if batch == nil {
+				p.work(ctx, j)
+				continue
+			}
+			// 已取走的任务必须全部执行完（ctx 结束时会被 run 跳过并释放计数），
+			// 否则它们占用的计数将无法释放
+			batch = p.dequeueMore(append(batch, j))
+			for i, j := range batch {
+				p.work(ctx, j)
+				batch[i] = nil
+			}
+			batch = batch[:0]
 		}
 	}
 }
 
-// executeWithRetry 根据配置执行任务，并在失败时进行重试。
-// 当超过最大重试次数后，会将最终错误加入错误收集器。
-func (p *Pool) executeWithRetry(ctx context.Context, task Task) {
-	var err error
-	// 统一 panic 恢复：无论是否开启重试，任务中的 panic
-	// 都会被转换为 error 并加入错误收集器，避免 worker 整体崩溃。
+// dequeueMore 在队列足够深时以非阻塞方式追加取出任务，直到 batch 填满。
+// 队列中的任务不多于 worker 数时不追加，让空闲的 worker 及时取到任务，避免增加延迟。
+func (p *Pool) dequeueMore(batch []*job) []*job {
+	for len(batch) < cap(batch) && len(p.tasks) > p.workers() {
+		select {
+		case j, ok := <-p.tasks:
+			if !ok {
+				return batch
+			}
+			p.addQueued(-1)
+			batch = append(batch, j)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// exec 执行一个出队的任务，并在可能时回收任务对象。
+func (p *Pool) exec(ctx context.Context, j *job) {
+	p.run(ctx, j)
+	// 带句柄的任务仍会被句柄引用，不能回收
+	if j.pooled && j.handle == nil {
+		j.release()
+	}
+}
+
+// run 在 worker 中执行一个出队的任务，并在结束后释放其计数。
+// 通过 SubmitWithContext 提交的任务使用合并后的 ctx；出队时 ctx 已结束或排队过久的任务会被跳过；
+// 对带句柄的任务，run 会为其派生独立的 ctx 并更新状态；若任务在排队期间已被取消，
+// 则直接跳过（取消方已负责释放计数）。
+func (p *Pool) run(ctx context.Context, j *job) {
+	if j.ctx != nil {
+		buf := workerBufferOf(ctx)
+		var cancel context.CancelFunc
+		ctx, cancel = mergeContext(j.ctx, ctx)
+		defer cancel()
+		// 合并后的 ctx 取的是提交方 ctx 中的值，需要重新附加所属的池（见 Checkpoint）与 worker 的暂存对象
+		ctx = context.WithValue(ctx, poolKey{}, p)
+		if buf != nil {
+			ctx = context.WithValue(ctx, workerBufferKey{}, buf)
+		}
+	}
+	// ctx 已结束的任务注定失败，直接跳过以免白白占用 worker
+	if err := ctx.Err(); err != nil {
+		p.skip(j, TaskCanceled, err, &p.stats.skipped)
+		return
+	}
+	var waited time.Duration
+	if !j.enqueuedAt.IsZero() {
+		waited = p.opts.clock.Now().Sub(j.enqueuedAt)
+		if p.stats.queueWait != nil {
+			p.stats.queueWait.observe(waited)
+		}
+		if p.opts.maxQueueAge > 0 && waited > p.opts.maxQueueAge {
+			if p.opts.onStale != nil {
+				p.opts.onStale(j.name, waited)
+			}
+			p.skip(j, TaskDiscarded, ErrStale, &p.stats.stale)
+			return
+		}
+	}
+	if p.opts.deadlinePropagation {
+		var cancel context.CancelFunc
+		ctx, cancel = p.shrinkDeadline(ctx, j, waited)
+		defer cancel()
+		if err := ctx.Err(); err != nil {
+			p.skip(j, TaskCanceled, err, &p.stats.skipped)
+			return
+		}
+	}
+	var shares shareSet
+	if err := p.acquireShares(ctx, &shares); err != nil {
+		p.skip(j, TaskCanceled, err, &p.stats.skipped)
+		return
+	}
+	if j.handle != nil {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		if !j.handle.start(cancel) {
+			shares.release()
+			return
+		}
+	}
+
+	if n := p.running.Add(1); p.opts.metrics != nil {
+		p.opts.metrics.SetGauge(MetricRunning, float64(n))
+	}
+	// worker 在任务结束前意外退出（runtime.Goexit、逃出的 panic）时，由 abortRun 结束任务并释放计数
+	var released, finished bool
 	defer func() {
-		if r := recover(); r != nil {
-			p.errs.Add(panicError(r))
+		if !finished {
+			p.abortRun(j, &shares, released)
+		}
+	}()
+	// 有 ResultsSeq 订阅方、结束回调、错误上报方或指标 sink 时同样需要执行耗时
+	var (
+		allocs  allocSnapshot
+		sampled bool
+	)
+	if p.opts.sampling != nil {
+		allocs, sampled = p.opts.sampling.begin()
+	}
+	timed := p.stats.exec != nil || p.results.active() || p.opts.onTaskComplete != nil || p.opts.reporter != nil || p.opts.metrics != nil ||
+		sampled || p.inline != nil
+	var start time.Time
+	if timed {
+		start = p.opts.clock.Now()
+	}
+	if j.softTimeout > 0 {
+		now := start
+		if !timed {
+			now = p.opts.clock.Now()
+		}
+		ctx = p.withSoftDeadline(ctx, now, j.softTimeout)
+	}
+	if j.idempotencyKey != "" {
+		ctx = context.WithValue(ctx, idempotencyKey{}, j.idempotencyKey)
+	}
+	var span Span
+	if p.opts.tracer != nil {
+		ctx, span = p.startSpan(ctx, j)
+	}
+	if j.lineage != nil {
+		j.lineage.ctx = ctx
+	}
+	attempts, err := p.executeWithRetry(ctx, j)
+	if err == errRetryLater {
+		// 退避期间不占用 worker：结束这一段执行，任务回到延迟队列
+		if span != nil {
+			span.End(j.retryErr)
+		}
+		n := p.running.Add(-1)
+		shares.release()
+		released = true
+		if m := p.opts.metrics; m != nil {
+			m.SetGauge(MetricRunning, float64(n))
+		}
+		p.retryLater(j)
+		finished = true
+		return
+	}
+	if span != nil {
+		span.End(err)
+	}
+	var elapsed time.Duration
+	if timed {
+		elapsed = p.opts.clock.Now().Sub(start)
+	}
+	if p.stats.exec != nil {
+		p.stats.exec.observe(elapsed)
+	}
+	if sampled {
+		p.finishSample(j, allocs, attempts, err, elapsed)
+	}
+	if p.inline != nil {
+		p.inline.observe(elapsed)
+	}
+	n := p.running.Add(-1)
+	shares.release()
+	released = true
+	if err != nil {
+		p.stats.failed.Add(1)
+	} else {
+		p.stats.succeeded.Add(1)
+	}
+	if m := p.opts.metrics; m != nil {
+		m.SetGauge(MetricRunning, float64(n))
+		reportRun(m, err, attempts, waited, elapsed)
+	}
+	if len(j.tags) > 0 {
+		p.tags.record(j.tags, err)
+	}
+	p.results.publish(TaskResult{Name: j.name, Lane: j.lane, Tags: j.tags, Attempts: attempts, Duration: elapsed}, err)
+	if j.handle != nil {
+		p.completed(j.handle.finish(err, waited, attempts), err)
+	} else if p.opts.onTaskComplete != nil || err != nil && p.opts.reporter != nil {
+		p.completed(p.runInfo(j, err, waited, attempts, start, elapsed), err)
+	}
+	j.acknowledge(err)
+	if j.after != nil {
+		j.after(err)
+	}
+	if j.coalesce != nil {
+		p.settleCoalesced(j, err)
+	}
+	p.done(j)
+	finished = true
+}
+
+// skip 以错误 err 结束一个出队后不再执行的任务（ctx 已结束或排队过久）：
+// 不计入 Errors，句柄置为 status，并递增计数 n。任务若已被取消则由取消方负责释放计数。
+func (p *Pool) skip(j *job, status TaskStatus, err error, n *atomic.Uint64) {
+	if j.handle != nil && !j.handle.settleIfQueued(status, err) {
+		return
+	}
+	n.Add(1)
+	if p.opts.metrics != nil {
+		reportSkipped(p.opts.metrics, status)
+	}
+	if p.opts.onTaskComplete != nil {
+		p.notifySkipped(j, status, err)
+	}
+	j.acknowledge(err)
+	if j.after != nil {
+		j.after(err)
+	}
+	if j.coalesce != nil {
+		p.settleCoalesced(j, err)
+	}
+	p.done(j)
+}
+
+// executeWithRetry 根据配置执行任务，并在失败时进行重试；重试耗尽后若任务设置了 WithFallback，
+// 以降级函数的结果取代错误。最终仍有错误时会将其加入错误收集器，并作为返回值返回
+// （panic 会被转换为 error 返回）。attempts 是任务实际被执行的次数，不含被限流或熔断拦截的尝试。
+func (p *Pool) executeWithRetry(ctx context.Context, j *job) (attempts int, err error) {
+	if p.plain && j.fallback == nil && p.retryLimit() == 0 && p.limiter() == nil {
+		return 1, p.executeOnce(ctx, j)
+	}
+	// 重新入队重试的任务从此前的执行次数继续
+	attempts = j.attempts
+	defer func() {
+		// 降级函数中的 panic 同样被恢复；内置恢复被替换或关闭时不在此处恢复，见 WithRecovery
+		if !p.opts.customRecovery {
+			if r := recover(); r != nil {
+				err = panicError(r)
+			}
+		}
+		// 任务将重新入队继续重试，尚无最终结果
+		if err == errRetryLater {
 			return
 		}
-		// 非 panic 场景下，如果最终仍有错误，则收集错误
+		if attempts > 1 {
+			p.stats.retries.Add(uint64(attempts - 1))
+		}
+		// 最终仍有错误时收集错误；命名或经过重试的任务的错误会带上任务名与执行次数
 		if err != nil {
+			err = j.wrapErr(err, attempts, p.opts.name)
 			p.errs.Add(err)
 		}
 	}()
 
-	for i := 0; i <= p.opts.retry; i++ {
-		err = task(ctx)
-		if err == nil {
-			return
+	err = p.retryLoop(ctx, j, &attempts)
+	// 被放弃的任务仍在运行，不执行降级以免与任务本身并发
+	if err != nil && j.fallback != nil && err != ErrTaskAbandoned && err != errRetryLater {
+		if err = j.fallback(ctx, err); err == nil {
+			p.stats.degraded.Add(1)
+		}
+	}
+	return attempts, err
+}
+
+// executeOnce 是 executeWithRetry 的快速路径：不重试、没有降级，且未启用限流、熔断、重试预算、
+// 硬超时、追踪与自定义恢复时，只用一个 defer 完成 panic 恢复与错误收集，
+// 省去重试循环及其中的逐项检查，使最简单的提交→执行路径尽量接近直接启动 goroutine 的开销。
+func (p *Pool) executeOnce(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(r)
+		}
+		if err != nil {
+			err = j.wrapErr(err, 1, p.opts.name)
+			p.errs.Add(err)
+		}
+	}()
+	return p.wrap(j.task).run(ctx)
+}
+
+// retryLoop 执行任务直到成功或重试耗尽，返回最后一次的错误，attempts 累计实际执行次数。
+func (p *Pool) retryLoop(ctx context.Context, j *job, attempts *int) (err error) {
+	// 统一 panic 恢复：无论是否开启重试，任务中的 panic
+	// 都会被转换为 error 并结束重试，避免 worker 整体崩溃（WithRetryOnPanic(true) 时已在每次尝试内恢复）。
+	span := p.taskSpan(ctx)
+	// probe 表示当前这次执行是熔断器半开状态下的试探执行
+	var probe bool
+	defer func() {
+		if !p.opts.customRecovery {
+			if r := recover(); r != nil {
+				err = panicError(r)
+				if p.breaker != nil {
+					p.breaker.record(err, probe)
+				}
+				if span != nil {
+					tracePanic(span, *attempts, err)
+				}
+			}
 		}
-		if p.opts.retryDelay > 0 {
-			time.Sleep(p.opts.retryDelay)
+	}()
+
+	retry := p.retryLimit()
+	for i := *attempts; i <= retry; i++ {
+		// 限流器控制任务启动速率；ctx 结束导致等待失败时不再继续重试。
+		// 先等 lane 的令牌再取全局令牌，被限流的 lane 不会占着全局令牌而拖慢其他 lane
+		if l := p.opts.laneLimiters[j.lane]; l != nil {
+			if err = l.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		if l := p.limiter(); l != nil {
+			if err = l.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		// 熔断器打开时直接短路，不再执行任务，也不再继续重试
+		if p.breaker != nil {
+			var ok bool
+			if ok, probe = p.breaker.allow(); !ok {
+				return ErrCircuitOpen
+			}
+		}
+		*attempts++
+		if *attempts == 1 && p.budget != nil {
+			p.budget.first()
 		}
+		err = p.recoverAttempt(attemptContext(ctx, *attempts), j)
+		if p.breaker != nil {
+			p.breaker.record(err, probe)
+		}
+		if span != nil && err != nil {
+			tracePanic(span, *attempts, err)
+		}
+		// 被放弃的任务仍在运行，重试只会泄漏更多 goroutine
+		if err == nil || err == ErrTaskAbandoned {
+			return err
+		}
+		if p.opts.retryOnPanicSet && !p.opts.retryOnPanic && isPanic(err) {
+			return err
+		}
+		// 最后一次执行失败后不再等待；重试预算耗尽时同样不再重试
+		if i == retry || (p.budget != nil && !p.budget.allowRetry()) {
+			break
+		}
+		d := p.nextRetryDelay(*attempts, err)
+		if span != nil {
+			traceRetry(span, *attempts, err, d)
+		}
+		if p.opts.reporter != nil {
+			p.reportRetriedPanic(j, err, *attempts)
+		}
+		if d > 0 {
+			if p.deferRetry(j, *attempts, err, d) {
+				return errRetryLater
+			}
+			p.opts.clock.Sleep(d)
+		}
+	}
+	return err
+}
+
+// nextRetryDelay 返回第 attempt 次执行因 err 失败后、下一次重试前的等待时间，
+// 错误携带的 RetryAfter 提示优先于配置的间隔。
+func (p *Pool) nextRetryDelay(attempt int, err error) time.Duration {
+	if d := retryAfter(err); d > 0 {
+		return d
 	}
+	if fn := p.opts.retryDelayFunc; fn != nil {
+		return fn(attempt, err)
+	}
+	return p.retryDelay()
 }
 
-// Wait 阻塞等待所有已提交任务执行完成，并在首次调用时关闭任务通道。
-// 多次调用是安全的（多次调用只会在第一次时真正关闭通道）。
+// Wait 阻塞等待所有已提交任务执行完成，随后执行关闭回调（见 OnShutdown）并关闭池。
+// 多次调用或并发调用都是安全的（只会真正关闭一次）。同步模式下只执行关闭回调。
+//
+// Wait 与提交并发时的约定：只要还有任务未结束，新的提交就会被接受并被 Wait 等待，
+// 因此任务中继续提交子任务是安全的；所有任务结束、池被关闭之后，
+// Submit 等所有提交接口都返回 ErrPoolClosed。
 func (p *Pool) Wait() {
-	p.wg.Wait()
-	// 通过 once 保证 tasks 只会被关闭一次，避免调用方误多次调用 Wait 时 panic。
+	p.shutdown(context.Background())
+}
+
+// shutdown 是 Wait 与 Shutdown 的共同实现，ctx 会被传给关闭回调。
+func (p *Pool) shutdown(ctx context.Context) {
+	if p.opts.synchronous {
+		p.once.Do(func() {
+			p.hooks.run(ctx)
+			p.markClosed()
+			close(p.closed)
+		})
+		return
+	}
+	p.inflight.close()
+	// 通过 once 保证 tasks 只会被关闭一次，并发调用的 Wait 也会等到关闭完成后才返回。
 	p.once.Do(func() {
+		if p.store != nil {
+			if err := p.store.close(); err != nil {
+				p.errs.Add(err)
+			}
+		}
+		if p.opts.queue != nil {
+			p.opts.queue.Close()
+		}
 		close(p.tasks)
+		p.hooks.run(ctx)
+		p.markClosed()
+		close(p.closed)
+		p.releaseKill()
 	})
 }
 
 // Errors 返回一个包含所有任务执行错误的切片副本。
-// 返回的是拷贝，调用方可以安全地在外部修改。
+// 返回的是拷贝，调用方可以安全地在外部修改。启用 WithErrorDedup 时相同信息的错误只保留第一个。
 func (p *Pool) Errors() []error {
 	return p.errs.Errors()
 }
+
+// ErrorsSeq 返回按收集顺序产出任务错误的迭代器，可直接用于 range-over-func 循环：
+// 先产出已收集的错误，此后池仍在运行时，每收集到新错误就继续产出，
+// 监控循环无需反复调用 Errors 复制整个切片。池被 Wait / Shutdown 关闭、
+// 且错误全部产出后遍历结束；提前结束遍历不影响错误的收集。
+func (p *Pool) ErrorsSeq() iter.Seq[error] {
+	return p.errs.follow(p.closed)
+}
+
+// ErrorsSummary 返回每种错误信息到其出现次数的映射，便于在大量重复错误中快速看清失败的分布。
+func (p *Pool) ErrorsSummary() map[string]int {
+	return p.errs.Summary()
+}
@@ -0,0 +1,49 @@
+package gopoolx
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// BenchmarkExecute 比较最简单的提交→执行路径（executeOnce 快速路径）、经过重试循环的路径，
+// 以及直接用信号量限制并发、为每个任务启动 goroutine 的写法。
+func BenchmarkExecute(b *testing.B) {
+	workers := runtime.GOMAXPROCS(0)
+	b.Run("fast-path", func(b *testing.B) {
+		benchmarkPool(b, New(workers, WithQueueSize(1024)))
+	})
+	b.Run("retry-loop", func(b *testing.B) {
+		benchmarkPool(b, New(workers, WithQueueSize(1024), WithRetry(1)))
+	})
+	b.Run("semaphore", func(b *testing.B) {
+		sem := make(chan struct{}, workers)
+		var wg sync.WaitGroup
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				noop(context.Background())
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+// benchmarkPool 向 p 提交 b.N 个空任务并等待它们执行完。
+func benchmarkPool(b *testing.B, p *Pool) {
+	go p.Run(context.Background())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Submit(noop)
+	}
+	p.Wait()
+}
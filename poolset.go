@@ -0,0 +1,112 @@
+package gopoolx
+
+import (
+	"context"
+	"hash/maphash"
+	"sync/atomic"
+)
+
+// Balance 定义 PoolSet 为未指定 key 的提交选择池的方式。
+type Balance int
+
+const (
+	// BalanceRoundRobin 依次轮流选择各个池（默认）
+	BalanceRoundRobin Balance = iota
+	// BalanceLeastLoaded 选择排队与执行中任务数之和最少的池
+	BalanceLeastLoaded
+)
+
+// PoolSet 将提交分发到多个池，对外提供与单个池相同的提交接口。
+// 适合按下游隔离 worker（每个下游一个池、互不拖累），又希望业务代码只面对一个提交入口的场景。
+// 典型用法：
+//
+//	set := gopoolx.NewPoolSet(gopoolx.BalanceLeastLoaded, poolA, poolB)
+//	set.Run(ctx)
+//	set.Submit(task)                          // 按负载选择
+//	set.SubmitKey(userID, task)               // 同一 key 总是进入同一个池
+//	f := gopoolx.SubmitWithResult(set.Next(), fn)
+//	set.Wait()
+type PoolSet struct {
+	pools   []*Pool
+	balance Balance
+	// next 是轮询的游标
+	next atomic.Uint64
+	seed maphash.Seed
+}
+
+var _ Submitter = (*PoolSet)(nil)
+
+// NewPoolSet 创建一个按 balance 分发提交的池集合，pools 不能为空。
+func NewPoolSet(balance Balance, pools ...*Pool) *PoolSet {
+	if len(pools) == 0 {
+		panic("gopoolx: NewPoolSet requires at least one pool")
+	}
+	return &PoolSet{
+		pools:   append([]*Pool(nil), pools...),
+		balance: balance,
+		seed:    maphash.MakeSeed(),
+	}
+}
+
+// Pools 返回集合中的池，顺序与创建时一致。
+func (s *PoolSet) Pools() []*Pool {
+	return append([]*Pool(nil), s.pools...)
+}
+
+// Next 按分发策略选出下一个池，可配合 SubmitWithResult 等以 *Pool 为参数的接口使用。
+func (s *PoolSet) Next() *Pool {
+	if s.balance == BalanceLeastLoaded {
+		best, load := s.pools[0], s.pools[0].load()
+		for _, p := range s.pools[1:] {
+			if l := p.load(); l < load {
+				best, load = p, l
+			}
+		}
+		return best
+	}
+	return s.pools[(s.next.Add(1)-1)%uint64(len(s.pools))]
+}
+
+// ForKey 返回 key 对应的池：相同的 key 总是对应同一个池，
+// 可用于保证同一对象的任务不会在不同池中并发执行。
+func (s *PoolSet) ForKey(key string) *Pool {
+	return s.pools[maphash.String(s.seed, key)%uint64(len(s.pools))]
+}
+
+// Submit 将任务提交到 Next 选出的池，语义与 Pool.Submit 相同。
+func (s *PoolSet) Submit(task Task, opts ...SubmitOption) error {
+	return s.Next().Submit(task, opts...)
+}
+
+// SubmitKey 将任务提交到 ForKey(key) 对应的池，语义与 Pool.Submit 相同。
+func (s *PoolSet) SubmitKey(key string, task Task, opts ...SubmitOption) error {
+	return s.ForKey(key).Submit(task, opts...)
+}
+
+// Run 以同一个 ctx 启动集合中的所有池。
+func (s *PoolSet) Run(ctx context.Context) {
+	for _, p := range s.pools {
+		p.Run(ctx)
+	}
+}
+
+// Wait 依次等待集合中的所有池完成并关闭。
+func (s *PoolSet) Wait() {
+	for _, p := range s.pools {
+		p.Wait()
+	}
+}
+
+// Errors 返回所有池收集的错误，按池的顺序拼接。
+func (s *PoolSet) Errors() []error {
+	var errs []error
+	for _, p := range s.pools {
+		errs = append(errs, p.Errors()...)
+	}
+	return errs
+}
+
+// load 返回池当前的负载：排队中与执行中的任务数之和。
+func (p *Pool) load() int {
+	return p.QueueLen() + p.Running()
+}
@@ -0,0 +1,179 @@
+// Package pooltest 提供测试依赖 gopoolx 的业务代码时使用的替身。
+//
+// Recorder 实现了 gopoolx.Submitter：它只记录被提交的任务而不执行，
+// 测试可以按顺序逐个执行任务，并断言每个任务的执行次数与错误。
+// 业务代码依赖 gopoolx.Submitter 而不是 *gopoolx.Pool 时即可注入：
+//
+//	rec := pooltest.New(pooltest.WithRetry(2))
+//	svc := NewService(rec)
+//	svc.Handle(req)
+//
+//	rec.RunAll(ctx)
+//	if calls := rec.Calls(); calls[0].Attempts != 3 {
+//		t.Fatalf("expected 3 attempts, got %d", calls[0].Attempts)
+//	}
+package pooltest
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"github.com/hyin49954/gopoolx"
+)
+
+// Call 是一次提交的记录。
+type Call struct {
+	// Index 是提交的序号，从 0 开始
+	Index int
+	// Task 是被提交的任务
+	Task gopoolx.Task
+	// Ran 表示任务是否已经被执行
+	Ran bool
+	// Attempts 是任务实际被执行的次数（首次执行 + 重试）
+	Attempts int
+	// Err 是任务重试耗尽后的最终错误，panic 会被转换为 error
+	Err error
+}
+
+// Option 是 Recorder 的配置项。
+type Option func(*Recorder)
+
+// WithRetry 设置执行失败时的重试次数，语义与 gopoolx.WithRetry 相同。
+func WithRetry(n int) Option {
+	return func(r *Recorder) {
+		r.retry = n
+	}
+}
+
+// WithSubmitError 使 Submit 总是返回 err 且不记录任务，用于测试提交失败的处理路径。
+func WithSubmitError(err error) Option {
+	return func(r *Recorder) {
+		r.submitErr = err
+	}
+}
+
+// Recorder 是记录型的假池，实现 gopoolx.Submitter，可在多个 goroutine 中并发使用。
+// 任务只会在测试调用 RunNext / RunAll 时执行，执行发生在调用方的 goroutine 中。
+type Recorder struct {
+	mu        sync.Mutex
+	retry     int
+	submitErr error
+	calls     []*Call
+	// next 是下一个待执行任务在 calls 中的下标
+	next int
+}
+
+var _ gopoolx.Submitter = (*Recorder)(nil)
+
+// New 创建一个 Recorder。
+func New(opts ...Option) *Recorder {
+	r := &Recorder{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Submit 记录任务，不执行。提交选项会被忽略。
+func (r *Recorder) Submit(task gopoolx.Task, _ ...gopoolx.SubmitOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.submitErr != nil {
+		return r.submitErr
+	}
+	r.calls = append(r.calls, &Call{Index: len(r.calls), Task: task})
+	return nil
+}
+
+// Len 返回已记录的提交次数。
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+// Pending 返回尚未执行的任务数。
+func (r *Recorder) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls) - r.next
+}
+
+// RunNext 按提交顺序执行下一个尚未执行的任务（含重试与 panic 恢复），
+// 返回其执行记录；没有待执行任务时 ok 为 false。
+// 任务执行期间不持有锁，任务中可以继续提交新任务。
+func (r *Recorder) RunNext(ctx context.Context) (call Call, ok bool) {
+	r.mu.Lock()
+	if r.next >= len(r.calls) {
+		r.mu.Unlock()
+		return Call{}, false
+	}
+	c := r.calls[r.next]
+	r.next++
+	retry := r.retry
+	r.mu.Unlock()
+
+	attempts, err := execute(ctx, c.Task, retry)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.Ran, c.Attempts, c.Err = true, attempts, err
+	return *c, true
+}
+
+// RunAll 依次执行所有待执行任务，包括执行过程中新提交的任务，返回执行的任务数。
+func (r *Recorder) RunAll(ctx context.Context) int {
+	n := 0
+	for {
+		if _, ok := r.RunNext(ctx); !ok {
+			return n
+		}
+		n++
+	}
+}
+
+// Calls 返回所有提交记录的副本，按提交顺序排列。
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := make([]Call, len(r.calls))
+	for i, c := range r.calls {
+		calls[i] = *c
+	}
+	return calls
+}
+
+// Errors 返回已执行任务的最终错误，按提交顺序排列，不含成功的任务。
+func (r *Recorder) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for _, c := range r.calls {
+		if c.Err != nil {
+			errs = append(errs, c.Err)
+		}
+	}
+	return errs
+}
+
+// execute 执行任务，失败时最多重试 retry 次，返回执行次数与最终错误。
+func execute(ctx context.Context, task gopoolx.Task, retry int) (attempts int, err error) {
+	for attempts < retry+1 {
+		attempts++
+		if err = runSafe(ctx, task); err == nil {
+			return attempts, nil
+		}
+	}
+	return attempts, err
+}
+
+// runSafe 执行一次任务，并将 panic 转换为 error，与池的处理方式一致。
+func runSafe(ctx context.Context, task gopoolx.Task) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = &gopoolx.PanicError{Value: rec, Stack: debug.Stack()}
+		}
+	}()
+	return task(ctx)
+}
@@ -0,0 +1,34 @@
+package gopoolx
+
+// defaultIOMultiplier 是 NewIOBound 未指定倍数时 worker 数相对 GOMAXPROCS 的倍数。
+const defaultIOMultiplier = 8
+
+// NewCPUBound 创建适合计算密集型任务的池：worker 数等于 GOMAXPROCS 并随其变化
+// （见 WithGOMAXPROCSTracking），更多的 worker 只会增加调度开销；
+// 队列长度为 worker 数的 2 倍，队列满时提交方等待，使生产速度被执行速度约束。
+// opts 在预设之后应用，可以覆盖其中任何一项。
+func NewCPUBound(opts ...Option) *Pool {
+	return newPreset(1, 2, opts)
+}
+
+// NewIOBound 创建适合 IO 密集型任务（网络请求、磁盘读写等）的池：任务大部分时间在等待，
+// worker 数取 GOMAXPROCS 的 concurrencyMultiplier 倍并随其变化，<= 0 时取 8；
+// 队列长度为 worker 数的 4 倍以吸收突发，队列满时提交方等待。
+// opts 在预设之后应用，可以覆盖其中任何一项。
+func NewIOBound(concurrencyMultiplier float64, opts ...Option) *Pool {
+	if concurrencyMultiplier <= 0 {
+		concurrencyMultiplier = defaultIOMultiplier
+	}
+	return newPreset(concurrencyMultiplier, 4, opts)
+}
+
+// newPreset 按 worker 倍数与队列倍数创建预设池。
+func newPreset(multiplier float64, queueFactor int, opts []Option) *Pool {
+	workers := procsWorkers(multiplier)
+	preset := []Option{
+		WithGOMAXPROCSTracking(multiplier),
+		WithQueueSize(workers * queueFactor),
+		WithQueueFullPolicy(QueueFullWait),
+	}
+	return New(workers, append(preset, opts...)...)
+}
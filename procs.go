@@ -0,0 +1,28 @@
+package gopoolx
+
+import (
+	"math"
+	"runtime"
+	"time"
+)
+
+// procsPollInterval 是 WithGOMAXPROCSTracking 检查 GOMAXPROCS 的间隔。
+const procsPollInterval = time.Second
+
+// WithGOMAXPROCSTracking 让 worker 数跟随 GOMAXPROCS：worker 数取 multiplier 乘以
+// runtime.GOMAXPROCS(0) 后向上取整（至少为 1），New 的 workerNum 参数被忽略。
+// Run 之后池每隔一秒检查一次，GOMAXPROCS 变化时（容器的 CPU 配额调整后由运行时更新，
+// 或程序自行调用 runtime.GOMAXPROCS）增减 worker：新增的 worker 立即开始取任务，
+// 多余的 worker 在执行完当前任务后退出。
+// 分派模式（WithDispatchMode）下只影响初始的 worker 数；已创建子池的执行名额上限不随之调整。
+// multiplier <= 0 表示不启用。
+func WithGOMAXPROCSTracking(multiplier float64) Option {
+	return func(o *Options) {
+		o.procsMultiplier = multiplier
+	}
+}
+
+// procsWorkers 按当前 GOMAXPROCS 计算 worker 数。
+func procsWorkers(multiplier float64) int {
+	return max(1, int(math.Ceil(multiplier*float64(runtime.GOMAXPROCS(0)))))
+}
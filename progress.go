@@ -0,0 +1,112 @@
+package gopoolx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// BatchOption 是 Batch 的可选配置。
+type BatchOption func(*Batch)
+
+// WithProgress 为批次设置进度回调：每完成 every 个任务（以及最后一个任务完成时）
+// 以当前的 (done, total) 调用一次 fn。回调在完成任务的 worker 中串行执行，应避免阻塞。
+func WithProgress(every int, fn func(done, total int)) BatchOption {
+	return func(b *Batch) {
+		if every < 1 {
+			every = 1
+		}
+		b.every = every
+		b.onProgress = fn
+	}
+}
+
+// Batch 是一组共享池 worker 的任务，提供独立的进度统计与错误收集，
+// 便于 CLI 或仪表盘展示长时间批量作业的完成百分比；长期运行的池也可以为每个请求创建一个批次，
+// 以 Wait 与 Errors 得到仅属于该请求的结果。
+// 典型用法：
+//
+//	b := pool.Batch(gopoolx.WithProgress(100, func(done, total int) {
+//		log.Printf("%d/%d", done, total)
+//	}))
+//	for _, item := range items {
+//		b.Submit(process(item))
+//	}
+//	b.Wait()
+//	errs := b.Errors()
+type Batch struct {
+	pool *Pool
+
+	// total 是已提交的任务数，finished 是已结束的任务数（无论成功、失败或被拒绝）
+	total    atomic.Int64
+	finished atomic.Int64
+	wg       sync.WaitGroup
+	// errs 收集本批次任务的最终错误
+	errs ErrorCollector
+
+	every      int
+	onProgress func(done, total int)
+	// progressMu 串行化进度回调
+	progressMu sync.Mutex
+}
+
+// Batch 创建一个在当前池上执行任务的批次。
+func (p *Pool) Batch(opts ...BatchOption) *Batch {
+	b := &Batch{pool: p}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Submit 向批次提交一个任务，语义与 Pool.Submit 相同。
+// 提交失败或被丢弃的任务同样计为已完成。
+func (b *Batch) Submit(task Task, opts ...SubmitOption) error {
+	b.total.Add(1)
+	b.wg.Add(1)
+
+	j := newJob(task, opts)
+	j.after = func(err error) {
+		b.finish(err)
+	}
+	err := b.pool.submit(j)
+	if err == ErrDiscarded {
+		b.finish(nil)
+		return nil
+	}
+	if err != nil {
+		b.finish(err)
+	}
+	return err
+}
+
+// Progress 返回批次当前的进度：已结束的任务数与已提交的任务总数。
+func (b *Batch) Progress() (done, total int) {
+	return int(b.finished.Load()), int(b.total.Load())
+}
+
+// Wait 阻塞直到批次中所有已提交的任务结束。它只等待本批次，不会关闭池。
+func (b *Batch) Wait() {
+	b.wg.Wait()
+}
+
+// Errors 返回本批次目前为止的错误副本（按完成顺序），包括执行失败、
+// 出队时被跳过与提交失败的任务，不包括被丢弃的任务。
+// 在 Wait 返回后调用可以得到本批次完整的错误列表。
+func (b *Batch) Errors() []error {
+	return b.errs.Errors()
+}
+
+// finish 记录一个任务以 err 结束，并按需触发进度回调。
+func (b *Batch) finish(err error) {
+	b.errs.Add(err)
+	done := b.finished.Add(1)
+	if b.onProgress != nil {
+		total := b.total.Load()
+		if done%int64(b.every) == 0 || done == total {
+			b.progressMu.Lock()
+			b.onProgress(int(done), int(total))
+			b.progressMu.Unlock()
+		}
+	}
+	b.wg.Done()
+}
@@ -0,0 +1,37 @@
+package gopoolx
+
+// Promise 是 Future 的生产者一端，可以在任意 goroutine 中手动完成对应的 Future。
+// 它让不经过池的异步来源（webhook、回调等）也能接入 Future 的消费方式，
+// 例如 Get、Then、All 等。
+type Promise[T any] struct {
+	f *Future[T]
+}
+
+// NewPromise 创建一对尚未完成的 Promise 与 Future。
+// Promise 可以按值传递，所有副本完成的是同一个 Future。
+func NewPromise[T any]() (Promise[T], *Future[T]) {
+	f := newFuture[T]()
+	return Promise[T]{f: f}, f
+}
+
+// Complete 以 res 与 err 完成 Future。Future 只会被完成一次：
+// 首次调用返回 true，之后的调用不产生效果并返回 false。
+func (p Promise[T]) Complete(res T, err error) bool {
+	return p.f.tryComplete(res, err)
+}
+
+// Resolve 以成功结果 v 完成 Future，语义同 Complete(v, nil)。
+func (p Promise[T]) Resolve(v T) bool {
+	return p.f.tryComplete(v, nil)
+}
+
+// Reject 以错误 err 完成 Future，语义同 Complete(零值, err)。
+func (p Promise[T]) Reject(err error) bool {
+	var zero T
+	return p.f.tryComplete(zero, err)
+}
+
+// Future 返回该 Promise 对应的 Future。
+func (p Promise[T]) Future() *Future[T] {
+	return p.f
+}
@@ -0,0 +1,162 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueClosed 由 TaskQueue.Pop 在队列已关闭且没有剩余任务时返回。
+var ErrQueueClosed = errors.New("task queue is closed")
+
+// QueueItem 是放入 TaskQueue 的任务，对队列实现是不透明的，只暴露排序或路由可能用到的元数据。
+type QueueItem struct {
+	j *job
+}
+
+// Name 返回任务名（见 WithTaskName）。
+func (it QueueItem) Name() string {
+	return it.j.name
+}
+
+// Lane 返回任务所属的通道（见 WithLane）。
+func (it QueueItem) Lane() string {
+	return it.j.lane
+}
+
+// TaskQueue 是池的可替换任务队列，通过 WithQueue 指定，取代默认的 Go 通道。
+// 实现可以是优先级堆、有界环形缓冲，或者按 lane 分组的公平队列等。所有方法都可能被并发调用。
+type TaskQueue interface {
+	// Push 将任务放入队列。实现可以在队列满时阻塞，也可以返回错误：
+	// 返回 ErrQueueFull（或包装了它的错误，如 *QueueFullError）时按返回错误模式处理（计入 Errors），返回 ErrDiscarded 时按丢弃处理，
+	// 其他错误原样返回给提交方。
+	Push(item QueueItem) error
+	// Pop 取出下一个任务，队列为空时阻塞，直到有任务、ctx 结束（返回 ctx 错误）
+	// 或队列关闭且已空（返回 ErrQueueClosed）。
+	Pop(ctx context.Context) (QueueItem, error)
+	// Len 返回队列中的任务数
+	Len() int
+	// Close 关闭队列，池在 Wait 结束时调用；此后 Pop 应在取完剩余任务后返回 ErrQueueClosed。
+	Close()
+}
+
+// WithQueue 使用自定义的任务队列 q 取代默认的 Go 通道。
+// 启用后 WithQueueSize 与队列满策略不再作用于入队（由 q.Push 的行为决定），
+// Run 会启动一个 goroutine 从 q 中取出任务交给空闲的 worker。
+func WithQueue(q TaskQueue) Option {
+	return func(o *Options) {
+		o.queue = q
+	}
+}
+
+// pushQueue 将已登记计数的任务放入自定义队列，失败时释放计数。
+func (p *Pool) pushQueue(j *job) error {
+	p.markQueued(j)
+	err := p.opts.queue.Push(QueueItem{j: j})
+	if err == nil {
+		return nil
+	}
+	p.addQueued(-1)
+	p.reject(j, err)
+	if errors.Is(err, ErrQueueFull) {
+		p.errs.Add(err)
+	}
+	return err
+}
+
+// pumpQueue 从自定义队列中取出任务并交给 worker，直到队列关闭或 ctx 结束。
+// 任务通道在启用自定义队列时是无缓冲的，因此任务只会在有空闲 worker 时才被取出，
+// 队列实现的排序得以保持。
+func (p *Pool) pumpQueue(ctx context.Context) {
+	for {
+		item, err := p.opts.queue.Pop(ctx)
+		if err != nil {
+			return
+		}
+		select {
+		case p.tasks <- item.j:
+		case <-ctx.Done():
+			p.addQueued(-1)
+			p.skip(item.j, TaskCanceled, ctx.Err(), &p.stats.skipped)
+			return
+		}
+	}
+}
+
+// NewFIFOQueue 返回一个不限长度的先进先出队列，可作为 TaskQueue 的参考实现：
+// Push 从不阻塞也不失败，适合提交方不能被阻塞、且内存足以容纳积压的场景。
+func NewFIFOQueue() TaskQueue {
+	return &fifoQueue{notify: make(chan struct{}, 1)}
+}
+
+// fifoQueue 是 NewFIFOQueue 返回的队列。
+type fifoQueue struct {
+	mu     sync.Mutex
+	items  []QueueItem
+	head   int
+	closed bool
+	// notify 在有新任务或队列关闭时唤醒等待中的 Pop
+	notify chan struct{}
+}
+
+func (q *fifoQueue) Push(item QueueItem) error {
+	q.mu.Lock()
+	q.items = append(q.items, item)
+	q.mu.Unlock()
+	q.wake()
+	return nil
+}
+
+func (q *fifoQueue) Pop(ctx context.Context) (QueueItem, error) {
+	for {
+		q.mu.Lock()
+		if q.head < len(q.items) {
+			item := q.items[q.head]
+			q.items[q.head] = QueueItem{}
+			q.head++
+			// 已取出的部分过半时整理底层数组，避免无限增长
+			if q.head > len(q.items)/2 {
+				q.items = append(q.items[:0], q.items[q.head:]...)
+				q.head = 0
+			}
+			more := q.head < len(q.items)
+			q.mu.Unlock()
+			if more {
+				q.wake()
+			}
+			return item, nil
+		}
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			return QueueItem{}, ErrQueueClosed
+		}
+
+		select {
+		case <-q.notify:
+		case <-ctx.Done():
+			return QueueItem{}, ctx.Err()
+		}
+	}
+}
+
+func (q *fifoQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items) - q.head
+}
+
+func (q *fifoQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.wake()
+}
+
+// wake 非阻塞地唤醒一个等待中的 Pop。
+func (q *fifoQueue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,106 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter 是任务启动前调用的限流器接口。
+// 方法签名与 golang.org/x/time/rate.Limiter 的 Wait 一致，
+// 因此 *rate.Limiter 可以直接通过 WithLimiter 传入使用。
+type Limiter interface {
+	// Wait 阻塞直到允许执行一次任务，或 ctx 结束时返回错误。
+	Wait(ctx context.Context) error
+}
+
+// tokenBucket 是内置的令牌桶限流器实现。
+// 令牌以 rate 个/秒的速度生成，桶中最多积攒 burst 个令牌。
+type tokenBucket struct {
+	mu sync.Mutex
+	// rate 是每秒生成的令牌数
+	rate float64
+	// burst 是桶容量，即允许的最大突发次数
+	burst float64
+	// tokens 是当前可用令牌数，可能为负数（表示已被预约的令牌）
+	tokens float64
+	// last 是最近一次补充令牌的时间
+	last time.Time
+	// clock 是计算令牌补充与等待使用的时钟
+	clock Clock
+}
+
+// newTokenBucket 创建一个初始为满桶的令牌桶。
+// burst 小于 1 时按 1 处理，避免限流器永远无法放行。
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		clock:  realClock{},
+	}
+}
+
+// setClock 将令牌桶切换到池的时钟，由 New 在应用 WithClock 后调用。
+func (b *tokenBucket) setClock(c Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = c
+	b.last = c.Now()
+}
+
+// Wait 预约一个令牌，并在令牌可用前阻塞等待。
+// 若 ctx 在等待期间结束，预约的令牌会被归还，并返回 ctx.Err()。
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := b.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		b.cancelReservation()
+		return ctx.Err()
+	}
+}
+
+// reserve 取走一个令牌，返回需要等待该令牌生成的时长。
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancelReservation 归还一次未被使用的预约令牌。
+func (b *tokenBucket) cancelReservation() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
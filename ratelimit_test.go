@@ -0,0 +1,44 @@
+package gopoolx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// tokenLimiter 只发放 tokens 中预先放入的令牌，用完后阻塞到 ctx 结束。
+type tokenLimiter struct{ tokens chan struct{} }
+
+func (l tokenLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newTokenLimiter 返回预先放入 n 个令牌的 tokenLimiter。
+func newTokenLimiter(n int) tokenLimiter {
+	l := tokenLimiter{tokens: make(chan struct{}, n)}
+	for i := 0; i < n; i++ {
+		l.tokens <- struct{}{}
+	}
+	return l
+}
+
+// TestLaneLimiterDoesNotHoldGlobalToken 等待 lane 令牌的任务不应先取走全局令牌，使其他 lane 饿死。
+func TestLaneLimiterDoesNotHoldGlobalToken(t *testing.T) {
+	p := newRunningPool(t, 2, WithLimiter(newTokenLimiter(1)), WithLaneLimiter("throttled", newTokenLimiter(0)))
+	started := make(chan struct{})
+	p.Submit(func(context.Context) error { return nil }, WithLane("throttled"))
+	p.Submit(func(context.Context) error {
+		close(started)
+		return nil
+	}, WithLane("other"))
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("a throttled lane starved another lane of the global token")
+	}
+}
@@ -0,0 +1,96 @@
+package gopoolx
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverlapPolicy 定义周期任务的上一次执行尚未结束时，新一轮触发的处理方式。
+type OverlapPolicy int
+
+const (
+	// OverlapSkip 上一次执行尚未结束时跳过本轮触发（默认）
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue 不论上一次是否结束，每轮触发都提交一次任务到队列
+	OverlapQueue
+)
+
+// WithOverlapPolicy 设置周期任务的重叠处理策略，仅对 SubmitEvery 生效。
+func WithOverlapPolicy(policy OverlapPolicy) SubmitOption {
+	return func(o *submitOptions) {
+		o.overlap = policy
+	}
+}
+
+// Recurring 是 SubmitEvery 返回的周期任务句柄，用于停止后续触发。
+type Recurring struct {
+	stopOnce sync.Once
+	// stop 在调用 Stop 时关闭
+	stop chan struct{}
+	// running 表示当前是否有一次执行正在排队或运行
+	running atomic.Bool
+}
+
+// Stop 停止周期任务的后续触发，已提交的执行不受影响。
+// 多次调用是安全的。
+func (r *Recurring) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// SubmitEvery 注册一个周期任务：每隔 interval 向池中提交一次 task，
+// 任务的执行同样享有池的并发控制、重试、panic 恢复与错误收集。
+// 上一次执行未结束时的行为由 WithOverlapPolicy 决定，默认跳过本轮。
+//
+// 周期任务在被停止前会计入 Wait 的等待范围：调用 Wait 前需先调用
+// Recurring.Stop，或结束 Run 的 ctx。
+// 池已被 Wait 关闭时返回一个已停止的周期任务。
+func (p *Pool) SubmitEvery(interval time.Duration, task Task, opts ...SubmitOption) *Recurring {
+	j := newJob(task, opts)
+	r := &Recurring{
+		stop: make(chan struct{}),
+	}
+
+	// 周期任务本身只占用一个在途计数，每次触发的执行会单独登记；
+	// 池已关闭时返回一个已停止的周期任务
+	if err := p.inflight.add(1); err != nil {
+		r.Stop()
+		return r
+	}
+	go p.runRecurring(interval, j, r)
+	return r
+}
+
+// runRecurring 是周期任务的触发循环，直到 Stop 或 Run 的 ctx 结束。
+func (p *Pool) runRecurring(interval time.Duration, tmpl *job, r *Recurring) {
+	defer p.inflight.done(1)
+
+	timer := p.opts.clock.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-p.quit:
+			return
+		case <-timer.C():
+		}
+		timer.Reset(interval)
+
+		if tmpl.overlap == OverlapSkip && !r.running.CompareAndSwap(false, true) {
+			continue
+		}
+
+		j := *tmpl
+		j.after = func(error) {
+			r.running.Store(false)
+		}
+		if err := p.submit(&j); err != nil {
+			// 未能提交（丢弃、队列满等），本轮执行视为已结束
+			r.running.Store(false)
+		}
+	}
+}
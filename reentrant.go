@@ -0,0 +1,139 @@
+package gopoolx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrWouldDeadlock 表示任务在 worker 中向同一个池提交子任务时队列已满，
+// 继续阻塞等待可能导致所有 worker 互相等待而死锁，提交被拒绝。
+var ErrWouldDeadlock = errors.New("submit from worker would deadlock")
+
+// ReentrantPolicy 定义 QueueFullWait 策略下，worker 中的任务向同一个池提交
+// 而队列已满时的处理方式。
+type ReentrantPolicy int
+
+const (
+	// ReentrantBlock 与普通提交一样阻塞等待。
+	// 所有 worker 都在等待入队时整个池会死锁。
+	ReentrantBlock ReentrantPolicy = iota
+	// ReentrantCallerRuns 在提交方所在的 worker 中直接执行该任务
+	ReentrantCallerRuns
+	// ReentrantError 拒绝提交并返回 ErrWouldDeadlock，错误同时计入 Errors
+	ReentrantError
+	// ReentrantOverflow 将任务放入不限长度的溢出列表，由 worker 优先取出执行（默认）。
+	// 递归派生子任务（如遍历目录树）无需任何配置即可安全使用
+	ReentrantOverflow
+)
+
+// WithReentrantPolicy 设置 worker 中的任务向同一个池提交、且队列已满时的处理策略，
+// 仅对 QueueFullWait 策略生效，默认为 ReentrantOverflow。
+// 除 ReentrantBlock 外，每个 worker 会登记自己的 goroutine，队列已满的提交会据此
+// 判断提交方是否为本池的 worker；队列未满时提交路径没有额外开销。
+// 注意只有在 worker goroutine 中直接发起的提交才会被识别，任务另起的 goroutine 不在此列。
+func WithReentrantPolicy(policy ReentrantPolicy) Option {
+	return func(o *Options) {
+		o.reentrant = policy
+		o.reentrantSet = true
+	}
+}
+
+// registerWorker 登记当前 goroutine 为本池的 worker，返回对应的注销函数。
+// 策略为 ReentrantBlock 时不做任何事。
+func (p *Pool) registerWorker(ctx context.Context) func() {
+	if p.opts.reentrant == ReentrantBlock {
+		return func() {}
+	}
+	id := goroutineID()
+	p.workerCtxs.Store(id, ctx)
+	return func() {
+		p.workerCtxs.Delete(id)
+	}
+}
+
+// workerContext 返回当前 goroutine 作为本池 worker 时的 ctx；不是 worker 时 ok 为 false。
+func (p *Pool) workerContext() (ctx context.Context, ok bool) {
+	v, ok := p.workerCtxs.Load(goroutineID())
+	if !ok {
+		return nil, false
+	}
+	return v.(context.Context), true
+}
+
+// enqueueReentrant 处理 worker 中的任务在队列已满时的提交，j 已登记计数。
+func (p *Pool) enqueueReentrant(ctx context.Context, j *job) error {
+	switch p.opts.reentrant {
+	case ReentrantOverflow:
+		// 提交方自己就是 worker，当前任务结束后它会回到循环中取走溢出任务，
+		// 因此溢出列表中的任务总能被执行
+		p.overflow.push(j)
+		return nil
+	case ReentrantCallerRuns:
+		p.addQueued(-1)
+		// 嵌套执行时外层任务仍在使用 worker 的暂存对象，内层任务改用新建的对象
+		if b := workerBufferOf(ctx); b != nil {
+			ctx = context.WithValue(ctx, workerBufferKey{}, &workerBuffer{newFn: b.newFn})
+		}
+		p.exec(ctx, j)
+		return nil
+	default:
+		p.addQueued(-1)
+		p.reject(j, ErrWouldDeadlock)
+		p.errs.Add(ErrWouldDeadlock)
+		return ErrWouldDeadlock
+	}
+}
+
+// overflowList 是 worker 中提交的任务在队列已满时的溢出列表，长度不受限制。
+type overflowList struct {
+	mu   sync.Mutex
+	jobs []*job
+	// n 是列表长度，供 worker 在不加锁的情况下快速判断是否有溢出任务
+	n atomic.Int64
+}
+
+// push 将任务加入溢出列表。
+func (o *overflowList) push(j *job) {
+	o.mu.Lock()
+	o.jobs = append(o.jobs, j)
+	o.mu.Unlock()
+	o.n.Add(1)
+}
+
+// pop 取出最近加入的任务，列表为空时返回 nil。
+// 后进先出使递归派生的子任务按深度优先执行，溢出列表的长度因此更可控。
+func (o *overflowList) pop() *job {
+	if o.n.Load() == 0 {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := len(o.jobs)
+	if n == 0 {
+		return nil
+	}
+	j := o.jobs[n-1]
+	o.jobs[n-1] = nil
+	o.jobs = o.jobs[:n-1]
+	o.n.Add(-1)
+	return j
+}
+
+// goroutineID 从当前 goroutine 的栈信息中解析其编号。
+// 开销较大，只在队列已满的慢路径上使用。
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	// 栈信息以 "goroutine 123 [running]:" 开头
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
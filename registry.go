@@ -0,0 +1,139 @@
+package gopoolx
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// TaskInfo 是任务元数据与状态的快照。
+type TaskInfo struct {
+	// Pool 是任务所在池的名称（见 WithName），未命名的池为空
+	Pool string
+	// ID 是任务在池内唯一的编号
+	ID uint64
+	// Name 是任务名（见 WithTaskName），匿名任务为空
+	Name string
+	// Lane 是任务所属的通道（见 WithLane）
+	Lane string
+	// Tags 是任务的标签（见 WithTags）
+	Tags []string
+	// Status 是快照时任务的状态
+	Status TaskStatus
+	// SubmittedAt / StartedAt / FinishedAt 是任务提交、开始与结束的时间，未发生时为零值
+	SubmittedAt time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	// Err 是任务的最终错误
+	Err error
+	// QueueWait 是任务出队前在队列中等待的时长，未记录入队时间时为 0
+	QueueWait time.Duration
+	// Attempts 是任务实际被执行的次数（含重试），未执行的任务为 0
+	Attempts int
+}
+
+// ExecDuration 返回任务的执行耗时（含全部重试），任务未执行或尚未结束时返回 0。
+func (info TaskInfo) ExecDuration() time.Duration {
+	if info.StartedAt.IsZero() || info.FinishedAt.IsZero() {
+		return 0
+	}
+	return info.FinishedAt.Sub(info.StartedAt)
+}
+
+// TaskFilter 用于筛选 Tasks 返回的任务，返回 true 表示保留。
+type TaskFilter func(info TaskInfo) bool
+
+// ByStatus 返回只保留指定状态任务的过滤器。
+func ByStatus(statuses ...TaskStatus) TaskFilter {
+	return func(info TaskInfo) bool {
+		return slices.Contains(statuses, info.Status)
+	}
+}
+
+// Tasks 返回池已知任务的快照，按编号升序排列；filter 为 nil 时返回全部。
+// 已知任务包括：
+//   - 所有尚未结束（排队中、执行中）的带句柄任务
+//   - 通过 WithTaskTracking 保留的最近结束的任务
+//
+// 未启用 WithTaskTracking 时，只有 SubmitWithHandle、SubmitWithResult 等
+// 创建了句柄的任务会被记录。适合在管理接口中展示"池当前在做什么"。
+func (p *Pool) Tasks(filter TaskFilter) []TaskInfo {
+	infos := p.registry.snapshot()
+	if filter != nil {
+		infos = slices.DeleteFunc(infos, func(info TaskInfo) bool {
+			return !filter(info)
+		})
+	}
+	slices.SortFunc(infos, func(a, b TaskInfo) int {
+		switch {
+		case a.ID < b.ID:
+			return -1
+		case a.ID > b.ID:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return infos
+}
+
+// handleRegistry 记录所有尚未结束的任务句柄，以及最近结束任务的快照。
+type handleRegistry struct {
+	mu      sync.Mutex
+	handles map[uint64]*TaskHandle
+	// history 是最近结束任务的环形缓冲，容量为 historySize
+	history     []TaskInfo
+	historySize int
+	// next 是环形缓冲中下一个写入位置
+	next int
+}
+
+// add 登记一个句柄。
+func (r *handleRegistry) add(h *TaskHandle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handles == nil {
+		r.handles = make(map[uint64]*TaskHandle)
+	}
+	r.handles[h.id] = h
+}
+
+// finish 移除一个已结束的句柄，并在启用历史记录时保存其最终快照。
+func (r *handleRegistry) finish(info TaskInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.handles, info.ID)
+
+	if r.historySize <= 0 {
+		return
+	}
+	if len(r.history) < r.historySize {
+		r.history = append(r.history, info)
+		return
+	}
+	r.history[r.next] = info
+	r.next = (r.next + 1) % r.historySize
+}
+
+// get 按编号查找尚未结束的句柄，不存在时返回 nil。
+func (r *handleRegistry) get(id uint64) *TaskHandle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.handles[id]
+}
+
+// snapshot 返回所有已知任务的快照（未排序）。
+func (r *handleRegistry) snapshot() []TaskInfo {
+	r.mu.Lock()
+	handles := make([]*TaskHandle, 0, len(r.handles))
+	for _, h := range r.handles {
+		handles = append(handles, h)
+	}
+	infos := append([]TaskInfo(nil), r.history...)
+	r.mu.Unlock()
+
+	for _, h := range handles {
+		infos = append(infos, h.Info())
+	}
+	return infos
+}
@@ -0,0 +1,190 @@
+package gopoolx
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Report 是池运行结果的结构化汇总，见 Pool.Report。字段带有 json 标签，可以直接附加到作业记录中。
+type Report struct {
+	// Pool 是池的名称（见 WithName），未命名的池为空
+	Pool string `json:"pool,omitempty"`
+	// Duration 是从 Run 启动（同步模式下为创建池）到池被 Wait / Shutdown 关闭的时长，
+	// 尚未关闭时截至生成报告的时刻
+	Duration time.Duration `json:"duration"`
+	// Total 是已有结果的任务数，即 Succeeded、Failed、Skipped 与 DroppedStale 之和
+	Total     uint64 `json:"total"`
+	Succeeded uint64 `json:"succeeded"`
+	// Degraded 是 Succeeded 中经 WithFallback 降级后才成功的任务数
+	Degraded     uint64 `json:"degraded,omitempty"`
+	Failed       uint64 `json:"failed"`
+	Skipped      uint64 `json:"skipped,omitempty"`
+	DroppedStale uint64 `json:"dropped_stale,omitempty"`
+	// Retries 是累计的重试次数（不含首次执行）
+	Retries uint64 `json:"retries"`
+	// FailuresByKind 按错误种类统计计入 Errors 的错误数，种类的取法见 ErrorKind
+	FailuresByKind map[string]int `json:"failures_by_kind,omitempty"`
+	// FailuresByTag 按标签（见 WithTags）统计最终失败的任务数，只包含有失败的标签
+	FailuresByTag map[string]uint64 `json:"failures_by_tag,omitempty"`
+	// Exec 与 QueueWait 是执行耗时与排队时长的分位数，仅在启用 WithLatencyHistogram 时有数据
+	Exec      LatencySummary `json:"exec"`
+	QueueWait LatencySummary `json:"queue_wait"`
+}
+
+// LatencySummary 是由耗时直方图估计的分位数，精度受桶边界限制（见 Histogram.Quantile）。
+type LatencySummary struct {
+	Count uint64        `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+}
+
+// summarize 由直方图快照计算分位数。
+func summarize(h Histogram) LatencySummary {
+	return LatencySummary{
+		Count: h.Count,
+		Mean:  h.Mean(),
+		P50:   h.Quantile(0.5),
+		P90:   h.Quantile(0.9),
+		P99:   h.Quantile(0.99),
+	}
+}
+
+// Report 汇总池的运行结果：各类任务总数、按错误种类与标签的失败分布、耗时分位数、重试次数与运行时长，
+// 适合在命令行批处理结束时打印（见 Report.String），或附加到作业记录中。通常在 Wait 之后调用；
+// 运行期间也可以调用，得到的是截至当时的近似快照。错误分布只覆盖 Errors 中保留了详情的错误
+// （见 WithErrorCollection）。
+func (p *Pool) Report() Report {
+	s := p.Stats()
+	r := Report{
+		Pool:         p.opts.name,
+		Duration:     p.lifetime(),
+		Total:        s.Succeeded + s.Failed + s.Skipped + s.DroppedStale,
+		Succeeded:    s.Succeeded,
+		Degraded:     s.Degraded,
+		Failed:       s.Failed,
+		Skipped:      s.Skipped,
+		DroppedStale: s.DroppedStale,
+		Retries:      s.Retries,
+		Exec:         summarize(s.Exec),
+		QueueWait:    summarize(s.QueueWait),
+	}
+	for _, err := range p.Errors() {
+		if r.FailuresByKind == nil {
+			r.FailuresByKind = make(map[string]int)
+		}
+		r.FailuresByKind[ErrorKind(err)]++
+	}
+	for tag, ts := range p.StatsByTag() {
+		if ts.Failed == 0 {
+			continue
+		}
+		if r.FailuresByTag == nil {
+			r.FailuresByTag = make(map[string]uint64)
+		}
+		r.FailuresByTag[tag] = ts.Failed
+	}
+	return r
+}
+
+// String 返回适合在终端打印的多行文本。
+func (r Report) String() string {
+	var b strings.Builder
+	name := "pool"
+	if r.Pool != "" {
+		name = fmt.Sprintf("pool %q", r.Pool)
+	}
+	fmt.Fprintf(&b, "%s: %d tasks in %v (%d succeeded, %d failed", name, r.Total, r.Duration, r.Succeeded, r.Failed)
+	if r.Skipped > 0 {
+		fmt.Fprintf(&b, ", %d skipped", r.Skipped)
+	}
+	if r.DroppedStale > 0 {
+		fmt.Fprintf(&b, ", %d stale", r.DroppedStale)
+	}
+	fmt.Fprintf(&b, "), %d retries\n", r.Retries)
+	if r.Exec.Count > 0 {
+		fmt.Fprintf(&b, "  exec:       mean %v  p50 %v  p90 %v  p99 %v\n", r.Exec.Mean, r.Exec.P50, r.Exec.P90, r.Exec.P99)
+	}
+	if r.QueueWait.Count > 0 {
+		fmt.Fprintf(&b, "  queue wait: mean %v  p50 %v  p90 %v  p99 %v\n", r.QueueWait.Mean, r.QueueWait.P50, r.QueueWait.P90, r.QueueWait.P99)
+	}
+	writeCounts(&b, "failures by kind", r.FailuresByKind)
+	writeCounts(&b, "failures by tag", r.FailuresByTag)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeCounts 按次数从多到少（相同时按名称）写出 counts。
+func writeCounts[N int | uint64](b *strings.Builder, title string, counts map[string]N) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := slices.SortedFunc(maps.Keys(counts), func(x, y string) int {
+		if counts[x] != counts[y] {
+			if counts[x] > counts[y] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(x, y)
+	})
+	fmt.Fprintf(b, "  %s:\n", title)
+	for _, k := range keys {
+		fmt.Fprintf(b, "    %6d  %s\n", counts[k], k)
+	}
+}
+
+// ErrorKind 返回 err 的种类，用于 Report 中的失败分布：剥去 *TaskError 后，panic 记为 "panic"，
+// 其余取错误链最内层的错误：自定义类型取其类型名（如 "*net.OpError"），errors.New 与 fmt.Errorf
+// 构造的普通错误没有可区分的类型，取其信息。
+func ErrorKind(err error) string {
+	var te *TaskError
+	for errors.As(err, &te) && te.Err != nil {
+		err = te.Err
+	}
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		return "panic"
+	}
+	for {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			break
+		}
+		err = inner
+	}
+	switch kind := fmt.Sprintf("%T", err); kind {
+	case "*errors.errorString", "*fmt.wrapError", "*fmt.wrapErrors", "*errors.joinError":
+		return err.Error()
+	default:
+		return kind
+	}
+}
+
+// markStarted 在第一次 Run（同步模式下为创建池）时记录开始时间，见 Report.Duration。
+func (p *Pool) markStarted() {
+	now := p.opts.clock.Now()
+	p.startedAt.CompareAndSwap(nil, &now)
+}
+
+// markClosed 记录池被关闭的时间。
+func (p *Pool) markClosed() {
+	now := p.opts.clock.Now()
+	p.closedAt.CompareAndSwap(nil, &now)
+}
+
+// lifetime 返回池从开始到关闭（尚未关闭时到现在）的时长，尚未开始时返回 0。
+func (p *Pool) lifetime() time.Duration {
+	start := p.startedAt.Load()
+	if start == nil {
+		return 0
+	}
+	if end := p.closedAt.Load(); end != nil {
+		return end.Sub(*start)
+	}
+	return p.opts.clock.Now().Sub(*start)
+}
@@ -0,0 +1,52 @@
+package gopoolx
+
+import "errors"
+
+// Reporter 接收任务的失败与 panic，用于接入 Sentry、Bugsnag 等错误上报服务，
+// 池本身不依赖其中任何一个。
+type Reporter interface {
+	// Report 以错误 err 与任务快照 info 上报一次失败
+	Report(err error, info TaskInfo)
+}
+
+// ReporterFunc 让普通函数实现 Reporter。
+type ReporterFunc func(err error, info TaskInfo)
+
+// Report 调用 f(err, info)。
+func (f ReporterFunc) Report(err error, info TaskInfo) {
+	f(err, info)
+}
+
+// WithErrorReporter 设置错误上报方 r。以下情况会调用 r.Report：
+//   - 任务重试耗尽（且降级失败）后的最终错误，info.Status 为 TaskFailed；panic 导致的失败
+//     以 *PanicError 上报，其中带有调用栈
+//   - 某次执行 panic 但随后仍会重试时，以该 *PanicError 上报，info.Status 为 TaskRunning、
+//     info.Attempts 为 panic 的那次执行
+//
+// 提交失败（队列已满、池已关闭等）与被跳过的任务不会上报。Report 在 worker 中同步调用，应避免阻塞。
+func WithErrorReporter(r Reporter) Option {
+	return func(o *Options) {
+		o.reporter = r
+	}
+}
+
+// reportRetriedPanic 在第 attempt 次执行的错误 err 是被恢复的 panic、且任务还会重试时上报它。
+func (p *Pool) reportRetriedPanic(j *job, err error, attempt int) {
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		return
+	}
+	info := TaskInfo{
+		Pool:        p.opts.name,
+		Name:        j.name,
+		Lane:        j.lane,
+		Tags:        j.tags,
+		Status:      TaskRunning,
+		SubmittedAt: j.enqueuedAt,
+	}
+	if j.handle != nil {
+		info = j.handle.Info()
+	}
+	info.Err, info.Attempts = err, attempt
+	p.opts.reporter.Report(err, info)
+}
@@ -0,0 +1,36 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReporterResultTaskPanic(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		reported []error
+	)
+	p := newRunningPool(t, 1, WithErrorReporter(ReporterFunc(func(err error, info TaskInfo) {
+		mu.Lock()
+		reported = append(reported, err)
+		mu.Unlock()
+	})))
+	f := SubmitWithResult(p, func(context.Context) (int, error) {
+		panic("boom")
+	})
+	if _, err := f.GetTimeout(time.Second); !isPanic(err) {
+		t.Fatalf("got %v, want *PanicError", err)
+	}
+	p.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 || !isPanic(reported[0]) {
+		t.Fatalf("reported %v, want one *PanicError", reported)
+	}
+	if s := p.Stats(); s.Failed != 1 || s.Succeeded != 0 {
+		t.Fatalf("Failed = %d, Succeeded = %d, want 1 and 0", s.Failed, s.Succeeded)
+	}
+}
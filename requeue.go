@@ -0,0 +1,46 @@
+package gopoolx
+
+import (
+	"errors"
+	"time"
+)
+
+// errRetryLater 表示任务将在退避结束后重新入队重试，由 retryLoop 返回给 run，不会暴露给调用方。
+var errRetryLater = errors.New("gopoolx: retry requeued")
+
+// WithRetryRequeue 让失败的任务不再占着 worker 等待重试间隔：任务带着"不早于"的时间戳回到延迟队列，
+// 退避期间 worker 继续执行其他任务，到期后任务重新入队，从下一次执行继续重试。
+// 适合重试间隔较长、或大量任务同时退避的场景；重试次数、预算、RetryAfter 提示等语义不变。
+//
+// 退避期间任务仍计入 Wait 与 WithMaxPending，句柄状态回到 TaskQueued（可以被取消），重新入队后
+// 按队列满策略处理，也会重新经历排队时长检查（WithMaxQueueAge）。Run 的 ctx 在到期前结束时任务以
+// ErrCanceled 结束。每段执行各自产生一个 span，耗时统计只覆盖实际执行的时间。重试间隔为 0 时仍在
+// worker 中立即重试；同步模式下不生效。与 OrderFIFO、OrderFIFOPerKey 不能同时使用。
+func WithRetryRequeue() Option {
+	return func(o *Options) {
+		o.retryRequeue = true
+	}
+}
+
+// deferRetry 判断第 attempts 次执行因 err 失败、需要等待 d 后重试的任务是否改为重新入队，
+// 是时记录续跑所需的状态。
+func (p *Pool) deferRetry(j *job, attempts int, err error, d time.Duration) bool {
+	if !p.opts.retryRequeue || p.opts.synchronous {
+		return false
+	}
+	j.attempts, j.retryErr, j.retryAt = attempts, err, p.opts.clock.Now().Add(d)
+	return true
+}
+
+// retryLater 将等待重试的任务放回延迟队列，到期后重新入队；调度 goroutine 已退出时以 ErrCanceled 结束任务。
+func (p *Pool) retryLater(j *job) {
+	// 任务稍后还会再次执行，不能被回收
+	j.pooled = false
+	p.stats.requeued.Add(1)
+	if j.handle != nil {
+		j.handle.requeue()
+	}
+	if !p.pushDelayed(j, j.retryAt) {
+		p.skip(j, TaskCanceled, ErrCanceled, &p.stats.skipped)
+	}
+}
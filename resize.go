@@ -0,0 +1,70 @@
+package gopoolx
+
+import (
+	"context"
+	"time"
+)
+
+// workers 返回当前的 worker 数。
+func (p *Pool) workers() int {
+	return int(p.size.Load())
+}
+
+// SetWorkers 在运行期间将 worker 数调整为 n：新增的 worker 立即开始取任务，
+// 多余的 worker 在执行完当前任务后退出。Run 之前调用时，Run 启动后随即调整到 n。
+// 分派模式（WithDispatchMode）与同步模式下不生效；启用 WithGOMAXPROCSTracking 时，
+// 下一次检查 GOMAXPROCS 会覆盖这里的设置。n < 1（OrderFIFO 下 n != 1）时不做任何事。
+func (p *Pool) SetWorkers(n int) {
+	if n < 1 || p.opts.ordering == OrderFIFO && n != 1 {
+		return
+	}
+	p.resizeMu.Lock()
+	defer p.resizeMu.Unlock()
+	// 只保留最新的目标值
+	select {
+	case <-p.resizeReq:
+	default:
+	}
+	p.resizeReq <- n
+}
+
+// resizeWorkers 按 SetWorkers 与 GOMAXPROCS（启用 WithGOMAXPROCSTracking 时）调整 worker 数，
+// 直到 ctx 结束或池被关闭。
+func (p *Pool) resizeWorkers(ctx context.Context) {
+	var (
+		timer  Timer
+		procsC <-chan time.Time
+	)
+	if p.opts.procsMultiplier > 0 {
+		timer = p.opts.clock.NewTimer(procsPollInterval)
+		defer timer.Stop()
+		procsC = timer.C()
+	}
+
+	target := p.workers()
+	for {
+		// 逐个通知多余的 worker 退出；忙碌的 worker 执行完当前任务后才会收到
+		var retire chan<- struct{}
+		if p.workers() > target {
+			retire = p.retire
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.closed:
+			return
+		case retire <- struct{}{}:
+			p.size.Add(-1)
+			continue
+		case n := <-p.resizeReq:
+			target = n
+		case <-procsC:
+			timer.Reset(procsPollInterval)
+			target = procsWorkers(p.opts.procsMultiplier)
+		}
+		for cur := p.workers(); cur < target; cur++ {
+			p.size.Add(1)
+			go p.worker(ctx)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package gopoolx
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskResult 是一个已执行任务的结果摘要，由 ResultsSeq 产出。
+type TaskResult struct {
+	// Name 是任务名（见 WithTaskName），匿名任务为空字符串
+	Name string
+	// Lane 是任务所属的通道（见 WithLane）
+	Lane string
+	// Tags 是任务的标签（见 WithTags）
+	Tags []string
+	// Attempts 是任务实际被执行的次数（含重试）
+	Attempts int
+	// Duration 是任务从开始执行到结束（含全部重试）的耗时
+	Duration time.Duration
+}
+
+// ResultsSeq 返回按完成顺序产出 (结果, 错误) 的迭代器，可直接用于 range-over-func 循环，
+// 对任务结果做流式的后处理。错误为任务的最终错误，成功时为 nil。
+//
+// 每次遍历只会看到开始遍历之后完成的任务；未被执行的任务（被取消、丢弃、排队过久等）不会出现。
+// 结果在遍历方处理期间会被缓存，worker 不会因遍历方处理缓慢而阻塞。
+// 池被 Wait / Shutdown 关闭、且缓存的结果全部产出后遍历结束；提前结束遍历即取消订阅。
+func (p *Pool) ResultsSeq() iter.Seq2[TaskResult, error] {
+	return func(yield func(TaskResult, error) bool) {
+		sub := p.results.subscribe()
+		defer p.results.unsubscribe(sub)
+
+		for {
+			for _, o := range sub.take() {
+				if !yield(o.res, o.err) {
+					return
+				}
+			}
+			select {
+			case <-sub.notify:
+			case <-p.closed:
+				// 所有任务都在池关闭前发布了结果，取完剩余的即可结束
+				for _, o := range sub.take() {
+					if !yield(o.res, o.err) {
+						return
+					}
+				}
+				return
+			}
+		}
+	}
+}
+
+// taskOutcome 是一条待产出的任务结果。
+type taskOutcome struct {
+	res TaskResult
+	err error
+}
+
+// resultHub 将任务结果分发给所有订阅方，零值即可使用。
+type resultHub struct {
+	mu   sync.Mutex
+	subs map[*resultSub]struct{}
+	// n 是订阅方数量，没有订阅方时 worker 无需加锁
+	n atomic.Int32
+}
+
+// resultSub 是一个订阅方，缓存尚未产出的结果。
+type resultSub struct {
+	mu    sync.Mutex
+	items []taskOutcome
+	// notify 在有新结果时唤醒等待中的遍历方
+	notify chan struct{}
+}
+
+// active 判断当前是否有订阅方。
+func (h *resultHub) active() bool {
+	return h.n.Load() > 0
+}
+
+// subscribe 注册一个新的订阅方。
+func (h *resultHub) subscribe() *resultSub {
+	sub := &resultSub{notify: make(chan struct{}, 1)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs == nil {
+		h.subs = make(map[*resultSub]struct{})
+	}
+	h.subs[sub] = struct{}{}
+	h.n.Add(1)
+	return sub
+}
+
+// unsubscribe 注销订阅方，丢弃其尚未产出的结果。
+func (h *resultHub) unsubscribe(sub *resultSub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+	h.n.Add(-1)
+}
+
+// publish 将一个任务结果发送给所有订阅方。
+func (h *resultHub) publish(res TaskResult, err error) {
+	if !h.active() {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		sub.mu.Lock()
+		sub.items = append(sub.items, taskOutcome{res: res, err: err})
+		sub.mu.Unlock()
+		select {
+		case sub.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// take 取走所有缓存的结果。
+func (s *resultSub) take() []taskOutcome {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := s.items
+	s.items = nil
+	return items
+}
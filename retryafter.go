@@ -0,0 +1,23 @@
+package gopoolx
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryAfterError 是携带重试等待提示的错误，例如由 HTTP 429 / 503 响应的 Retry-After 头构造的客户端错误。
+// 任务返回的错误（或其包装链中的任一错误）实现该接口时，下一次重试前等待 RetryAfter 返回的时长，
+// 而不是 WithRetryDelay / WithRetryDelayFunc 配置的间隔；返回值 <= 0 时仍使用配置的间隔。
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+// retryAfter 返回 err 中携带的重试等待提示，没有时返回 0。
+func retryAfter(err error) time.Duration {
+	var ra RetryAfterError
+	if errors.As(err, &ra) {
+		return ra.RetryAfter()
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package gopoolx
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// retryBudgetWindow 是重试预算的统计窗口
+	retryBudgetWindow = 10 * time.Second
+	// retryBudgetMinRetries 是每个窗口内总是允许的重试次数，
+	// 避免流量很小的池因为首次执行太少而完全无法重试
+	retryBudgetMinRetries = 10
+)
+
+// WithRetryBudget 为整个池设置重试预算：每个统计窗口（10 秒）内的重试次数
+// 最多为首次执行次数的 ratio 倍（另外总是允许少量重试），例如 0.2 表示重试最多占首次执行的 20%。
+// 预算耗尽后，失败的任务不再重试，直接以本次执行的错误结束，
+// 避免下游故障时重试成倍放大请求量、形成重试风暴。ratio <= 0 表示不启用。
+func WithRetryBudget(ratio float64) Option {
+	return func(o *Options) {
+		o.retryBudget = ratio
+	}
+}
+
+// retryBudget 按固定窗口统计首次执行与重试次数，决定是否还允许重试。
+type retryBudget struct {
+	mu    sync.Mutex
+	ratio float64
+	// clock 是划分统计窗口使用的时钟
+	clock Clock
+
+	// windowStart 是当前窗口的开始时间
+	windowStart time.Time
+	// firsts / retries 是当前窗口内的首次执行次数与已放行的重试次数
+	firsts  int
+	retries int
+}
+
+// newRetryBudget 创建一个重试预算，当前窗口从此刻开始。
+func newRetryBudget(ratio float64, clock Clock) *retryBudget {
+	return &retryBudget{
+		ratio:       ratio,
+		clock:       clock,
+		windowStart: clock.Now(),
+	}
+}
+
+// rollLocked 在当前窗口结束时开启新窗口，调用方需持有 b.mu。
+func (b *retryBudget) rollLocked() {
+	if now := b.clock.Now(); now.Sub(b.windowStart) >= retryBudgetWindow {
+		b.windowStart = now
+		b.firsts, b.retries = 0, 0
+	}
+}
+
+// first 记录一次首次执行。
+func (b *retryBudget) first() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollLocked()
+	b.firsts++
+}
+
+// allowRetry 判断是否还允许一次重试，允许时从预算中扣除。
+func (b *retryBudget) allowRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollLocked()
+	if float64(b.retries) >= b.ratio*float64(b.firsts)+retryBudgetMinRetries {
+		return false
+	}
+	b.retries++
+	return true
+}
@@ -0,0 +1,123 @@
+package gopoolx
+
+import (
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// 资源采样通过 MetricsSink 上报的指标名。
+const (
+	// MetricHeavyTasks 是采样中超过阈值的任务数（计数器）
+	MetricHeavyTasks = "gopoolx_heavy_tasks_total"
+	// MetricSampledAllocBytes 是被采样任务执行期间的堆分配字节数（计数器）
+	MetricSampledAllocBytes = "gopoolx_sampled_alloc_bytes_total"
+)
+
+// ResourceSampling 配置按任务的资源采样，见 WithResourceSampling。
+type ResourceSampling struct {
+	// Every 表示每 Every 个执行的任务采样一次，<= 1 表示每个任务都采样
+	Every int
+	// MinWall 与 MinAllocBytes 是判定重任务的阈值，超过任一阈值即为重任务，0 表示不按该项判断；
+	// 二者都为 0 时每个被采样的任务都会上报
+	MinWall       time.Duration
+	MinAllocBytes uint64
+	// OnHeavy 以重任务的资源用量调用，可为 nil；在 worker 中同步调用，应避免阻塞
+	OnHeavy func(usage ResourceUsage)
+}
+
+// ResourceUsage 是一次被采样任务的资源用量。
+type ResourceUsage struct {
+	// Name、Lane 与 Tags 来自任务的提交选项
+	Name string
+	Lane string
+	Tags []string
+	// Attempts 是任务的执行次数，Err 是最终错误
+	Attempts int
+	Err      error
+	// Wall 是执行耗时（含重试）
+	Wall time.Duration
+	// AllocBytes 与 AllocObjects 是执行期间整个进程的堆分配量，并发执行的任务会计入彼此，
+	// 只是近似值，适合在大量样本中比较不同类型任务的开销
+	AllocBytes   uint64
+	AllocObjects uint64
+}
+
+// WithResourceSampling 启用按任务的资源采样：对被采样的任务记录执行耗时与期间的堆分配量
+// （通过 runtime/metrics 读取，每次采样约需数百纳秒），超过阈值的任务以 cfg.OnHeavy 上报；
+// 配置了 WithMetricsSink 时同时计入 MetricHeavyTasks 与 MetricSampledAllocBytes。
+// 用于找出哪类任务占据了主要开销；负载很高时可以调大 cfg.Every 降低采样开销。
+func WithResourceSampling(cfg ResourceSampling) Option {
+	return func(o *Options) {
+		o.sampling = &resourceSampler{cfg: cfg}
+	}
+}
+
+// resourceSampler 执行资源采样。
+type resourceSampler struct {
+	cfg ResourceSampling
+	// n 是已执行的任务数，用于按 Every 选择样本
+	n atomic.Uint64
+}
+
+// allocSnapshot 是某一时刻的累计堆分配量。
+type allocSnapshot struct {
+	bytes, objects uint64
+}
+
+// readAllocs 读取进程启动以来的累计堆分配量。
+func readAllocs() allocSnapshot {
+	s := [2]metrics.Sample{
+		{Name: "/gc/heap/allocs:bytes"},
+		{Name: "/gc/heap/allocs:objects"},
+	}
+	metrics.Read(s[:])
+	return allocSnapshot{bytes: sampleUint(s[0]), objects: sampleUint(s[1])}
+}
+
+// sampleUint 返回样本的整数值，运行时不支持该指标时返回 0。
+func sampleUint(s metrics.Sample) uint64 {
+	if s.Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return s.Value.Uint64()
+}
+
+// begin 决定本次执行是否采样，采样时返回执行前的分配量。
+func (s *resourceSampler) begin() (allocSnapshot, bool) {
+	if every := s.cfg.Every; every > 1 && s.n.Add(1)%uint64(every) != 0 {
+		return allocSnapshot{}, false
+	}
+	return readAllocs(), true
+}
+
+// finishSample 计算被采样任务的资源用量，并在超过阈值时上报。
+func (p *Pool) finishSample(j *job, before allocSnapshot, attempts int, err error, elapsed time.Duration) {
+	s := p.opts.sampling
+	after := readAllocs()
+	u := ResourceUsage{
+		Name:         j.name,
+		Lane:         j.lane,
+		Tags:         j.tags,
+		Attempts:     attempts,
+		Err:          err,
+		Wall:         elapsed,
+		AllocBytes:   after.bytes - before.bytes,
+		AllocObjects: after.objects - before.objects,
+	}
+	if m := p.opts.metrics; m != nil {
+		m.IncCounter(MetricSampledAllocBytes, u.AllocBytes)
+	}
+	heavy := s.cfg.MinWall == 0 && s.cfg.MinAllocBytes == 0 ||
+		s.cfg.MinWall > 0 && u.Wall >= s.cfg.MinWall ||
+		s.cfg.MinAllocBytes > 0 && u.AllocBytes >= s.cfg.MinAllocBytes
+	if !heavy {
+		return
+	}
+	if m := p.opts.metrics; m != nil {
+		m.IncCounter(MetricHeavyTasks, 1)
+	}
+	if s.cfg.OnHeavy != nil {
+		s.cfg.OnHeavy(u)
+	}
+}
@@ -0,0 +1,95 @@
+package gopoolx
+
+import (
+	"context"
+	"iter"
+)
+
+// MapSeq 使用 pool 并发地对 seq 产出的每个元素执行 fn，
+// 返回按完成顺序产出 (结果, 错误) 的迭代器，适合处理无法预先放入切片的无界流。
+// 说明：
+//   - 只有在遍历返回的迭代器时才会开始消费 seq 并提交任务
+//   - 提交受池的队列满策略约束，默认策略下会自然形成背压
+//   - 调用方提前结束遍历（break）或 ctx 结束时，停止消费 seq，
+//     未开始的元素不再执行，运行中的元素收到取消信号
+func MapSeq[T, R any](
+	ctx context.Context,
+	pool *Pool,
+	seq iter.Seq[T],
+	fn func(ctx context.Context, item T) (R, error),
+) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan Result[R])
+		go func() {
+			defer close(results)
+			runEach(ctx, pool, until(ctx, indexed(seq)), func(ctx context.Context, i int, item T) error {
+				res, err := fn(ctx, item)
+				if err != nil {
+					return err
+				}
+				select {
+				case results <- Result[R]{Index: i, Value: res}:
+				case <-ctx.Done():
+				}
+				return nil
+			}, func(i int, err error) {
+				if err == nil {
+					return
+				}
+				select {
+				case results <- Result[R]{Index: i, Err: err}:
+				case <-ctx.Done():
+				}
+			})
+		}()
+
+		for r := range results {
+			if !yield(r.Value, r.Err) {
+				cancel()
+				// 等待已提交的任务结束，保证返回后不再有任务访问调用方数据
+				for range results {
+				}
+				return
+			}
+		}
+	}
+}
+
+// ForEachSeq 使用 pool 对 seq 产出的每个元素执行 fn，并等待全部结束。
+// 错误聚合与 StopOnError 的语义与 ForEach 相同；ctx 结束时停止消费 seq。
+func ForEachSeq[T any](
+	ctx context.Context,
+	pool *Pool,
+	seq iter.Seq[T],
+	fn func(ctx context.Context, item T) error,
+	opts ...ForEachOption,
+) error {
+	return forEach(ctx, pool, indexed(seq), fn, opts)
+}
+
+// indexed 为 seq 产出的元素按顺序编号。
+func indexed[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for item := range seq {
+			if !yield(i, item) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// until 在 ctx 结束后停止产出 seq 的元素，避免无界流在取消后无法终止。
+func until[K, V any](ctx context.Context, seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if ctx.Err() != nil || !yield(k, v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrKilled 是 Kill 取消执行中任务的 ctx 时使用的原因，可通过 context.Cause(ctx) 取得。
+var ErrKilled = errors.New("pool killed")
+
+// shutdownHooks 保存通过 OnShutdown 注册的关闭回调。
+type shutdownHooks struct {
+	mu    sync.Mutex
+	hooks []func(ctx context.Context)
+	// ran 表示回调已执行，此后注册的回调不再执行
+	ran bool
+}
+
+// OnShutdown 注册一个关闭回调，用于按正确的顺序释放为池创建的资源（worker 使用的数据库连接池、临时目录等）。
+// 回调在池停止接收任务、所有任务结束之后，Wait / Shutdown 返回之前执行；
+// 多个回调按注册的逆序依次执行（与 defer 相同），期间不再有任务在运行。
+// 回调收到的 ctx 为 Shutdown 的 ctx（通过 Wait 关闭时为 context.Background()）。
+// 池关闭之后注册的回调不会执行。
+func (p *Pool) OnShutdown(hook func(ctx context.Context)) {
+	p.hooks.mu.Lock()
+	defer p.hooks.mu.Unlock()
+	if !p.hooks.ran {
+		p.hooks.hooks = append(p.hooks.hooks, hook)
+	}
+}
+
+// run 按注册的逆序执行所有回调，只会执行一次。
+func (h *shutdownHooks) run(ctx context.Context) {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks, h.ran = nil, true
+	h.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i](ctx)
+	}
+}
+
+// Shutdown 与 Wait 相同：等待所有已提交任务执行完成、执行关闭回调并关闭池，
+// 但最多等待到 ctx 结束。池在 ctx 结束前关闭完成时返回 nil，否则返回 ctx 的错误，
+// 此时关闭在后台继续进行，可以再次调用 Wait 或 Shutdown 等待其完成。
+func (p *Pool) Shutdown(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		p.shutdown(ctx)
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StopAccepting 是两阶段关闭的第一步：立即停止接收新任务，此后的提交（包括任务中派生的子任务、
+// 周期任务的下一次执行）返回 ErrPoolClosed；已接受的任务（排队中、执行中与尚未到期的延迟任务）照常执行。
+// 它不会阻塞，也不会关闭池，之后仍需调用 Wait 或 Shutdown 等待排空；
+// 宽限期内未能排空时可以调用 Kill 强制结束。重复调用是安全的。
+func (p *Pool) StopAccepting() {
+	p.inflight.stopped.Store(true)
+}
+
+// Kill 是两阶段关闭的第二步：停止接收新任务，并以 ErrKilled 为原因取消 Run 的 ctx，随即返回。
+// 执行中的任务收到 ctx 取消信号，仍在排队的任务不再执行、以 ctx 的错误结束（与 Run 的 ctx 结束时相同），
+// 任务响应取消后 Wait 即可返回。Kill 不等待任务结束；在 Run 之前调用时，Run 启动的 worker 会立即退出。
+func (p *Pool) Kill() {
+	p.StopAccepting()
+	p.killMu.Lock()
+	defer p.killMu.Unlock()
+	p.killed = true
+	if p.kill != nil {
+		p.kill(ErrKilled)
+	}
+}
+
+// killable 从 Run 的 ctx 派生可被 Kill 取消的 ctx。
+func (p *Pool) killable(ctx context.Context) context.Context {
+	ctx, cancel := context.WithCancelCause(ctx)
+	p.killMu.Lock()
+	defer p.killMu.Unlock()
+	p.kill = cancel
+	if p.killed {
+		cancel(ErrKilled)
+	}
+	return ctx
+}
+
+// releaseKill 在池关闭后释放 killable 派生的 ctx，此时已没有任务在运行。
+func (p *Pool) releaseKill() {
+	p.killMu.Lock()
+	defer p.killMu.Unlock()
+	if p.kill != nil {
+		p.kill(nil)
+	}
+}
@@ -0,0 +1,54 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ErrGraceExpired 表示收到退出信号后，池未能在宽限期内排空。
+var ErrGraceExpired = errors.New("pool did not drain within grace period")
+
+// RunUntilSignal 启动 p，并阻塞到收到 signals 中的任一信号（未指定时为 SIGINT 与 SIGTERM）
+// 或 ctx 结束，随后通过 Shutdown 优雅退出：等待已提交的任务执行完成并关闭池，最长等待 grace（<= 0 表示不限）。
+//
+// worker 的 ctx 不随 ctx 取消，排空期间任务照常执行，任务中提交的子任务也会被等待。
+// 宽限期内排空时返回 nil；超时则取消 worker 的 ctx（执行中的任务随之收到取消信号，
+// 仍在排队的任务不再执行）并返回 ErrGraceExpired，此时池可能尚未关闭。
+func RunUntilSignal(ctx context.Context, p *Pool, grace time.Duration, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	sigCtx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	p.Run(runCtx)
+
+	<-sigCtx.Done()
+	// 排空期间再次收到信号时按默认行为处理（通常是直接退出进程）
+	stop()
+
+	// 关闭回调收到的 ctx 在宽限期结束时取消
+	shutdownCtx, cancelShutdown := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelShutdown()
+	if grace > 0 {
+		timer := p.opts.clock.NewTimer(grace)
+		defer timer.Stop()
+		go func() {
+			select {
+			case <-timer.C():
+				cancelShutdown()
+			case <-shutdownCtx.Done():
+			}
+		}()
+	}
+	if p.Shutdown(shutdownCtx) != nil {
+		return ErrGraceExpired
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSourceDone 由 TaskSource.Next 返回，表示任务源已经没有更多任务，Serve 随之正常返回。
+var ErrSourceDone = errors.New("task source done")
+
+// TaskSource 是按需拉取任务的任务源，用于对接分页 API、消息队列等拉取型生产者。
+// Next 返回下一个任务，没有任务可取时应阻塞到有任务或 ctx 结束；任务源耗尽时返回 ErrSourceDone。
+// Next 只会在 Serve 的 goroutine 中串行调用。
+type TaskSource interface {
+	Next(ctx context.Context) (Task, error)
+}
+
+// TaskSourceFunc 让普通函数实现 TaskSource。
+type TaskSourceFunc func(ctx context.Context) (Task, error)
+
+// Next 调用 f(ctx)。
+func (f TaskSourceFunc) Next(ctx context.Context) (Task, error) {
+	return f(ctx)
+}
+
+// WithPrefetch 设置 Serve 在空闲 worker 之外预先拉取的任务数 n（默认 0）。预取的任务在队列中等待，
+// worker 执行完当前任务后无需等待 Next 的往返即可取到下一个，吞吐更高；代价是预取的任务占用内存，
+// 并且在真正开始执行前就已从消息队列取出，n 应小到足以保证它们在可见性超时（或租约）到期前开始执行。
+// 队列容量（WithQueueSize）不足以容纳预取的任务时，Serve 按队列满策略等待或处理。n < 0 视为 0。
+func WithPrefetch(n int) Option {
+	return func(o *Options) {
+		o.prefetch = max(n, 0)
+	}
+}
+
+// Serve 持续从 src 拉取任务并提交到池中，只有在池中有空闲 worker 时才调用 src.Next：
+// 经 Serve 提交、尚未结束的任务数最多等于 worker 数（在 Serve 开始时确定）加上 WithPrefetch 的预取数，
+// 默认不预取，任务不会在池的队列中堆积，消息的可见性超时等约束因此不会因预取而被突破。
+// opts 作用于每一个拉取到的任务。
+//
+// Serve 会阻塞：Next 返回 ErrSourceDone 时返回 nil，返回其他错误时原样返回该错误，
+// ctx 结束时返回 ctx 错误，池已关闭时返回 ErrPoolClosed；返回时已提交的任务仍由池照常执行。
+// 其他提交失败（例如返回错误模式下队列已满）按 Submit 的语义处理并继续拉取。
+func (p *Pool) Serve(ctx context.Context, src TaskSource, opts ...SubmitOption) error {
+	return p.serve(ctx, func(ctx context.Context) (*job, error) {
+		task, err := src.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return newJob(task, opts), nil
+	})
+}
+
+// Message 是至少一次投递的消息源交付的一个任务及其确认回调，见 ServeMessages。
+type Message struct {
+	// Task 是处理该消息的任务
+	Task Task
+	// Ack 在任务最终成功后调用（如提交偏移量、删除消息），Nack 在最终失败时调用（如让消息重新投递），
+	// 语义见 WithAck，均可为 nil
+	Ack  func()
+	Nack func(err error)
+}
+
+// MessageSource 是交付 Message 的任务源，约定与 TaskSource 相同：耗尽时返回 ErrSourceDone。
+type MessageSource interface {
+	Next(ctx context.Context) (Message, error)
+}
+
+// MessageSourceFunc 让普通函数实现 MessageSource。
+type MessageSourceFunc func(ctx context.Context) (Message, error)
+
+// Next 调用 f(ctx)。
+func (f MessageSourceFunc) Next(ctx context.Context) (Message, error) {
+	return f(ctx)
+}
+
+// ServeMessages 与 Serve 相同，但每个任务带有消息自身的确认回调（见 WithAck）：
+// Ack / Nack 在池的重试结束之后才调用，偏移量提交与重新投递因此与重试机制对齐。
+// ServeMessages 返回时，已提交任务的确认回调仍会在它们结束时调用。
+func (p *Pool) ServeMessages(ctx context.Context, src MessageSource, opts ...SubmitOption) error {
+	return p.serve(ctx, func(ctx context.Context) (*job, error) {
+		m, err := src.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		j := newJob(m.Task, opts)
+		j.ack, j.nack = m.Ack, m.Nack
+		return j, nil
+	})
+}
+
+// serve 是 Serve 与 ServeMessages 的共同实现，next 拉取下一个任务并构建对应的 job。
+func (p *Pool) serve(ctx context.Context, next func(ctx context.Context) (*job, error)) error {
+	slots := make(chan struct{}, p.workers()+p.opts.prefetch)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		j, err := next(ctx)
+		if err != nil {
+			if errors.Is(err, ErrSourceDone) {
+				return nil
+			}
+			return err
+		}
+
+		j.after = func(error) {
+			<-slots
+		}
+		// 未被接受的任务不会调用 after，由这里归还名额
+		if err := p.submit(j); err != nil {
+			<-slots
+			if err == ErrPoolClosed {
+				return err
+			}
+		}
+	}
+}
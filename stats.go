@@ -0,0 +1,109 @@
+package gopoolx
+
+import "sync/atomic"
+
+// Stats 是池运行统计的快照，各计数自池创建起累计。
+type Stats struct {
+	// Succeeded 是执行成功的任务数，Degraded 是其中经 WithFallback 降级后才成功的任务数
+	Succeeded uint64
+	Degraded  uint64
+	// Failed 是重试耗尽后仍失败（含 panic）的任务数
+	Failed uint64
+	// Skipped 是出队时 ctx 已结束、因而未执行直接以 ctx 错误结束的任务数
+	Skipped uint64
+	// DroppedStale 是因排队超过 WithMaxQueueAge 而被丢弃的任务数
+	DroppedStale uint64
+	// Retries 是全池累计的重试次数（不含首次执行），用于衡量重试带来的重复工作量
+	Retries uint64
+	// Spilled 是因队列已满而转交给溢出池（见 WithOverflowPool）执行的任务数
+	Spilled uint64
+	// Abandoned 是因 WithHardTimeout 被放弃的任务数
+	Abandoned uint64
+	// Leaked 是被放弃但仍在运行的 goroutine 数
+	Leaked int64
+	// WorkerRestarts 是 worker goroutine 意外退出后被替换的次数，见 WithOnWorkerRestart
+	WorkerRestarts uint64
+	// Inlined 是因池饱和而在提交方直接执行的任务数，见 WithInlineThreshold
+	Inlined uint64
+	// Coalesced 是合并到同键窗口中、没有单独执行的提交数，见 WithCoalesceWindow
+	Coalesced uint64
+	// Filtered 是被准入过滤器拒绝的提交数，见 WithAdmissionFilter
+	Filtered uint64
+	// Requeued 是失败后回到延迟队列等待重试、而不是占着 worker 等待的次数，见 WithRetryRequeue
+	Requeued uint64
+	// Errors 是计入 Errors 的错误总数，ErrorsDropped 是其中因去重或超出 LimitN 上限
+	// 而未保留详情的错误数
+	Errors        uint64
+	ErrorsDropped uint64
+	// QueueWait 与 Exec 分别是任务排队等待时间与执行时间（含重试）的直方图，
+	// 仅在启用 WithLatencyHistogram 时有数据
+	QueueWait Histogram
+	Exec      Histogram
+}
+
+// poolStats 保存池的运行计数，由 worker 并发更新。
+type poolStats struct {
+	succeeded atomic.Uint64
+	degraded  atomic.Uint64
+	failed    atomic.Uint64
+	skipped   atomic.Uint64
+	stale     atomic.Uint64
+	retries   atomic.Uint64
+	abandoned atomic.Uint64
+	spilled   atomic.Uint64
+	leaked    atomic.Int64
+	restarts  atomic.Uint64
+	inlined   atomic.Uint64
+	coalesced atomic.Uint64
+	filtered  atomic.Uint64
+	requeued  atomic.Uint64
+
+	// queueWait 与 exec 是耗时直方图，未启用 WithLatencyHistogram 时为 nil
+	queueWait *histogram
+	exec      *histogram
+}
+
+// Stats 返回池当前的运行统计快照。各字段分别原子读取，彼此之间不保证严格一致。
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Succeeded:      p.stats.succeeded.Load(),
+		Degraded:       p.stats.degraded.Load(),
+		Failed:         p.stats.failed.Load(),
+		Skipped:        p.stats.skipped.Load(),
+		DroppedStale:   p.stats.stale.Load(),
+		Retries:        p.stats.retries.Load(),
+		Spilled:        p.stats.spilled.Load(),
+		Abandoned:      p.stats.abandoned.Load(),
+		Leaked:         p.stats.leaked.Load(),
+		WorkerRestarts: p.stats.restarts.Load(),
+		Inlined:        p.stats.inlined.Load(),
+		Coalesced:      p.stats.coalesced.Load(),
+		Filtered:       p.stats.filtered.Load(),
+		Requeued:       p.stats.requeued.Load(),
+		Errors:         p.errs.total.Load(),
+		ErrorsDropped:  p.errs.dropped.Load(),
+		QueueWait:      p.stats.queueWait.snapshot(),
+		Exec:           p.stats.exec.snapshot(),
+	}
+}
+
+// QueueLen、Running 与 Pending 是供准入控制使用的轻量仪表：每次调用只读取一个原子计数，
+// 不加任何锁、不分配内存，可以在每个请求的热路径上（例如负载削减中间件）放心调用。
+// 三者分别读取，彼此之间不保证严格一致。
+
+// QueueLen 返回当前在队列中等待执行的任务数（含 QueueFullWait 下正阻塞等待入队的任务），
+// 不含尚未到期的延迟任务。
+func (p *Pool) QueueLen() int {
+	return int(p.queued.Load())
+}
+
+// Running 返回 worker 当前正在执行的任务数。
+func (p *Pool) Running() int {
+	return int(p.running.Load())
+}
+
+// Pending 返回已提交但尚未结束的任务数，即排队中、执行中与尚未到期的延迟任务之和，
+// 也就是 Wait 仍需等待的数量；每个未停止的周期任务（SubmitEvery）额外计为 1。
+func (p *Pool) Pending() int {
+	return int(p.inflight.state.Load() &^ inflightClosed)
+}
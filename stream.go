@@ -0,0 +1,98 @@
+package gopoolx
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// ResultStream 以流的形式交付多个带返回值任务的结果：
+// 每个任务结束后，其 (值, 错误) 立即出现在结果通道中，
+// 消费方可以边执行边处理下游工作，而不必等待全部任务完成。
+// 典型用法：
+//
+//	s := gopoolx.NewResultStream[int](pool, 0)
+//	go func() {
+//		defer s.Close()
+//		for _, id := range ids {
+//			s.Submit(fetch(id))
+//		}
+//	}()
+//	for r := range s.C() {
+//		...
+//	}
+type ResultStream[T any] struct {
+	pool *Pool
+	ch   chan Result[T]
+
+	mu sync.Mutex
+	// next 是下一个提交的序号，作为 Result.Index
+	next int
+	// closed 表示已调用 Close，不再接受新的提交
+	closed    bool
+	closeOnce sync.Once
+	// inflight 等待已提交任务的结果全部交付后关闭通道
+	inflight sync.WaitGroup
+}
+
+// NewResultStream 创建一个将任务提交到 pool 的结果流，buffer 为结果通道的缓冲大小。
+// 结果通道缓冲已满时，worker 会阻塞在交付结果上，消费方需持续读取。
+func NewResultStream[T any](pool *Pool, buffer int) *ResultStream[T] {
+	return &ResultStream[T]{
+		pool: pool,
+		ch:   make(chan Result[T], buffer),
+	}
+}
+
+// Submit 提交一个带返回值的任务，返回它在流中的序号（即 Result.Index）。
+// 语义与 SubmitWithResult 相同；提交失败或被丢弃时，错误同样作为结果交付。
+// Close 之后调用 Submit 会返回 -1 与 ErrPoolClosed。
+func (s *ResultStream[T]) Submit(fn func(ctx context.Context) (T, error), opts ...SubmitOption) (int, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return -1, ErrPoolClosed
+	}
+	idx := s.next
+	s.next++
+	s.inflight.Add(1)
+	s.mu.Unlock()
+
+	submitFunc(s.pool, fn, opts, func(res T, err error) {
+		s.ch <- Result[T]{Index: idx, Value: res, Err: err}
+		s.inflight.Done()
+	})
+	return idx, nil
+}
+
+// Close 表示不再提交新的任务。已提交任务的结果全部交付后，结果通道会被关闭。
+// 多次调用是安全的。
+func (s *ResultStream[T]) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+
+		go func() {
+			s.inflight.Wait()
+			close(s.ch)
+		}()
+	})
+}
+
+// C 返回结果通道，结果按完成顺序到达。
+func (s *ResultStream[T]) C() <-chan Result[T] {
+	return s.ch
+}
+
+// All 返回按完成顺序产出 (值, 错误) 的迭代器，直到结果通道关闭。
+// 提前结束遍历不会停止已提交的任务，剩余结果仍需从 C 中读取。
+func (s *ResultStream[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for r := range s.ch {
+			if !yield(r.Value, r.Err) {
+				return
+			}
+		}
+	}
+}
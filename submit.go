@@ -1,44 +1,143 @@
 package gopoolx
 
-import "context"
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
 
 // SubmitWithResult 提交一个带返回值的任务到指定的 Pool 中，并返回一个 Future 用于异步获取结果。
 // 说明：
 //   - fn 会在池中的 worker goroutine 中执行
 //   - 若 fn 正常返回，其结果与错误会写入 Future
+//   - 若 fn 返回错误，会按池的配置进行重试，Future 在最终结果确定后才完成
 //   - 若 fn 发生 panic，会被捕获并转换为 error 返回到 Future
+//   - opts 为单次提交的可选配置，与 Pool.Submit 相同
 func SubmitWithResult[T any](
 	pool *Pool,
 	fn func(ctx context.Context) (T, error),
+	opts ...SubmitOption,
 ) *Future[T] {
 
 	future := newFuture[T]()
+	future.pool = pool
 
-	// 将带返回值的函数包装成 Pool 所需的 Task 形式
-	if err := pool.Submit(func(ctx context.Context) error {
-		var (
-			res T
-			err error
-		)
-
-		// 通过 defer 捕获 panic，保证无论成功、失败还是 panic，
-		// Future 都能被正确标记为"已完成"并唤醒等待方。
-		defer func() {
-			if r := recover(); r != nil {
-				var zero T
-				future.complete(zero, panicError(r))
-				return
+	j := resultJob(pool, fn, opts, future.complete)
+	future.handle = newTaskHandle(pool, j)
+	future.lineage = newLineage(j)
+	if err := pool.submit(j); err != nil {
+		var zero T
+		future.complete(zero, err)
+	}
+	return future
+}
+
+// SubmitBatchWithResults 将 fns 中的每个函数作为一个任务提交到 pool，返回在全部任务结束后完成的 Future：
+//   - 结果按 fns 的顺序排列，失败的函数在结果中为零值
+//   - 错误按 fns 的顺序聚合所有失败（errors.Join），全部成功时为 nil；失败时仍会交付其余函数的结果
+//   - 每个函数按池的配置重试，panic 会被转换为 error，提交失败或被丢弃同样计为失败
+//
+// 它取代了"循环调用 SubmitWithResult、再逐个 Get"的样板代码。fns 为空时立即以空切片完成。
+func SubmitBatchWithResults[T any](
+	pool *Pool,
+	fns []func(ctx context.Context) (T, error),
+	opts ...SubmitOption,
+) *Future[[]T] {
+	out := newFuture[[]T]()
+	out.pool = pool
+	if len(fns) == 0 {
+		out.complete([]T{}, nil)
+		return out
+	}
+
+	results := make([]T, len(fns))
+	errs := make([]error, len(fns))
+	var remaining atomic.Int64
+	remaining.Store(int64(len(fns)))
+	for i, fn := range fns {
+		submitFunc(pool, fn, opts, func(res T, err error) {
+			results[i], errs[i] = res, err
+			if remaining.Add(-1) == 0 {
+				out.complete(results, errors.Join(errs...))
 			}
-			future.complete(res, err)
-		}()
+		})
+	}
+	return out
+}
 
-		res, err = fn(ctx)
-		return err
-	}); err != nil {
-		// 如果提交失败（如队列满且策略为返回错误），立即完成 Future 并返回错误
+// submitFunc 将带返回值的函数包装为任务提交到 pool，是 SubmitWithResult 等封装的共同实现。
+// 在全部重试结束后（或提交失败、任务被丢弃时）以最终结果调用且只调用一次 complete。
+func submitFunc[T any](
+	pool *Pool,
+	fn func(ctx context.Context) (T, error),
+	opts []SubmitOption,
+	complete func(res T, err error),
+) {
+	j := resultJob(pool, fn, opts, complete)
+	if err := pool.submit(j); err != nil {
+		// 如果提交失败（如队列满且策略为返回错误）或任务被丢弃，
+		// 立即交付错误，避免等待方永远阻塞
 		var zero T
-		future.complete(zero, err)
+		complete(zero, err)
 	}
+}
 
-	return future
+// submitFuncAsync 将 fn 作为继承了 from 的属性（见 Then）的任务，通过 submitAsync 异步提交，
+// 用于在任务完成回调等 worker 上下文中派生后续任务；任务结束后以最终结果完成 next。
+func submitFuncAsync[T any](
+	pool *Pool,
+	fn func(ctx context.Context) (T, error),
+	from *lineage,
+	next *Future[T],
+) {
+	j := resultJob(pool, fn, from.options(), next.complete)
+	j.ctx = from.context()
+	next.lineage = newLineage(j)
+	pool.submitAsync(j, func(err error) {
+		var zero T
+		next.complete(zero, err)
+	})
+}
+
+// resultJob 将带返回值的函数包装为队列中的任务，任务结束后以最终结果调用 complete。
+//
+// fn 中的 panic 与 Pool.Submit 的任务一样交给池的内置恢复，从而计入失败、上报给 WithErrorReporter
+// 并遵循 WithRetryOnPanic；内置恢复被替换或关闭时（见 WithRecovery）在这里恢复，
+// 保证 panic 仍以 *PanicError 交付给 complete。
+func resultJob[T any](
+	pool *Pool,
+	fn func(ctx context.Context) (T, error),
+	opts []SubmitOption,
+	complete func(res T, err error),
+) *job {
+	var res T
+	recovers := pool.opts.customRecovery
+	// 将带返回值的函数包装成 Pool 所需的 Task 形式
+	j := newJob(func(ctx context.Context) (err error) {
+		// 每次执行重新开始，避免重试时残留上一次执行的返回值
+		var zero T
+		res = zero
+		if recovers {
+			defer func() {
+				if r := recover(); r != nil {
+					res, err = zero, panicError(r)
+				}
+			}()
+		}
+		res, err = fn(ctx)
+		return err
+	}, opts)
+
+	// 在全部重试结束后才完成，保证无论成功、失败还是 panic，
+	// 结果都只会被交付一次。
+	j.after = func(err error) {
+		// 合并执行时，没有实际执行的提交取得实际执行的任务的返回值
+		if g := j.coalesce; g != nil {
+			if v, ok := g.share(j, res).(T); ok {
+				res = v
+			}
+		}
+		complete(res, err)
+	}
+	return j
 }
@@ -0,0 +1,70 @@
+package gopoolx
+
+import "errors"
+
+// ErrWorkerExited 表示任务执行期间 worker goroutine 意外退出（如任务或其依赖的库调用了
+// runtime.Goexit），任务以该错误结束并计入 Errors。
+var ErrWorkerExited = errors.New("worker exited unexpectedly")
+
+// WithOnWorkerRestart 设置 worker 替换回调。worker goroutine 意外退出时（任务调用了 runtime.Goexit，
+// 或回调中的 panic 逃出了 worker 循环），池会启动一个新的 worker 顶替它，保持 worker 数不变，
+// 计入 Stats().WorkerRestarts，并以退出原因调用 fn：reason 是恢复得到的 panic 值，Goexit 时为 nil。
+// 被中断的任务以 ErrWorkerExited 结束，其计数照常释放，Wait 不会因此阻塞。
+//
+// 替换总会发生，回调只用于告警与排查，可为 nil。内置恢复被替换或关闭时（见 WithRecovery）
+// 逃出的 panic 不会被恢复，进程仍会崩溃。fn 在退出的 worker goroutine 中调用。
+func WithOnWorkerRestart(fn func(reason any)) Option {
+	return func(o *Options) {
+		o.onWorkerRestart = fn
+	}
+}
+
+// supervise 运行 worker 循环 loop；loop 未正常返回（panic 或 runtime.Goexit）时
+// 记录一次替换并调用 respawn 启动新的 worker。
+func (p *Pool) supervise(loop, respawn func()) {
+	exited := false
+	defer func() {
+		if exited {
+			return
+		}
+		var reason any
+		if !p.opts.customRecovery {
+			reason = recover()
+		}
+		p.stats.restarts.Add(1)
+		if fn := p.opts.onWorkerRestart; fn != nil {
+			fn(reason)
+		}
+		respawn()
+	}()
+	loop()
+	exited = true
+}
+
+// abortRun 结束 worker 意外退出时正在执行的任务 j。released 表示 run 已释放执行名额与运行计数，
+// 此时只需补齐句柄状态并释放任务计数。
+func (p *Pool) abortRun(j *job, shares *shareSet, released bool) {
+	if !released {
+		n := p.running.Add(-1)
+		shares.release()
+		p.stats.failed.Add(1)
+		if m := p.opts.metrics; m != nil {
+			m.SetGauge(MetricRunning, float64(n))
+		}
+		p.errs.Add(j.wrapErr(ErrWorkerExited, 1, p.opts.name))
+	}
+	if j.handle != nil {
+		j.handle.abort(ErrWorkerExited)
+	}
+	if !released {
+		j.acknowledge(ErrWorkerExited)
+		// 与 skip 相同交付结果，否则 SubmitWithResult、Group 等依赖结束回调的封装会一直等待
+		if j.after != nil {
+			j.after(ErrWorkerExited)
+		}
+	}
+	if j.coalesce != nil {
+		p.settleCoalesced(j, ErrWorkerExited)
+	}
+	p.done(j)
+}
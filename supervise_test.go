@@ -0,0 +1,46 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGoexitCompletesFuture(t *testing.T) {
+	p := newRunningPool(t, 1)
+	f := SubmitWithResult(p, func(context.Context) (int, error) {
+		runtime.Goexit()
+		return 1, nil
+	})
+	if _, err := f.GetTimeout(time.Second); !errors.Is(err, ErrWorkerExited) {
+		t.Fatalf("got %v, want ErrWorkerExited", err)
+	}
+	p.Wait()
+	if got := p.Stats().WorkerRestarts; got != 1 {
+		t.Fatalf("WorkerRestarts = %d, want 1", got)
+	}
+}
+
+func TestGoexitCompletesGroup(t *testing.T) {
+	p := newRunningPool(t, 2)
+	g := p.Group(context.Background())
+	g.Go(func() error {
+		runtime.Goexit()
+		return nil
+	})
+	g.Go(func() error { return nil })
+
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrWorkerExited) {
+			t.Fatalf("got %v, want ErrWorkerExited", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Group.Wait did not return after a worker exited")
+	}
+	p.Wait()
+}
@@ -0,0 +1,139 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// WithTags 为任务打上一个或多个标签（例如 WithTags("import", "tenant42")），可多次使用。
+// 一个池承载多种工作负载时，可以通过 ErrorsByTag 与 StatsByTag 把失败归属到具体的负载。
+func WithTags(tags ...string) SubmitOption {
+	return func(o *submitOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// TagStats 是某个标签下已执行任务的统计。
+type TagStats struct {
+	// Succeeded 是执行成功的任务数
+	Succeeded uint64
+	// Failed 是最终失败的任务数
+	Failed uint64
+}
+
+// tagIndex 按标签记录已执行任务的结果，零值即可使用。
+type tagIndex struct {
+	// entries 保存 string -> *tagEntry
+	entries sync.Map
+}
+
+// tagEntry 是单个标签的统计与错误。
+type tagEntry struct {
+	succeeded atomic.Uint64
+	failed    atomic.Uint64
+	errs      ErrorCollector
+
+	mu sync.Mutex
+	// pending 是带该标签、已登记但尚未结束的任务数
+	pending int64
+	// idle 在 pending 归零时关闭，pending 再次大于 0 时重新创建
+	idle chan struct{}
+}
+
+// entry 返回标签 tag 的记录，不存在时创建。
+func (t *tagIndex) entry(tag string) *tagEntry {
+	v, ok := t.entries.Load(tag)
+	if !ok {
+		v, _ = t.entries.LoadOrStore(tag, new(tagEntry))
+	}
+	return v.(*tagEntry)
+}
+
+// begin 为一个已登记的任务递增其所有标签的未结束计数。
+func (t *tagIndex) begin(tags []string) {
+	for _, tag := range tags {
+		e := t.entry(tag)
+		e.mu.Lock()
+		if e.pending == 0 {
+			e.idle = make(chan struct{})
+		}
+		e.pending++
+		e.mu.Unlock()
+	}
+}
+
+// end 为一个结束的任务递减其所有标签的未结束计数，归零时唤醒 WaitTag。
+func (t *tagIndex) end(tags []string) {
+	for _, tag := range tags {
+		e := t.entry(tag)
+		e.mu.Lock()
+		if e.pending--; e.pending == 0 {
+			close(e.idle)
+		}
+		e.mu.Unlock()
+	}
+}
+
+// record 将任务的最终结果计入其所有标签。
+func (t *tagIndex) record(tags []string, err error) {
+	for _, tag := range tags {
+		e := t.entry(tag)
+		if err != nil {
+			e.failed.Add(1)
+			e.errs.Add(err)
+		} else {
+			e.succeeded.Add(1)
+		}
+	}
+}
+
+// ErrorsByTag 返回带有标签 tag 的任务的最终错误，按发生顺序排列，没有错误时返回 nil。
+// 与 Errors 不同，它只包含执行失败的任务，不含队列已满等提交失败。
+func (p *Pool) ErrorsByTag(tag string) []error {
+	v, ok := p.tags.entries.Load(tag)
+	if !ok {
+		return nil
+	}
+	return v.(*tagEntry).errs.Errors()
+}
+
+// StatsByTag 返回每个标签下已执行任务的统计快照，未打标签的任务不计入。
+func (p *Pool) StatsByTag() map[string]TagStats {
+	stats := make(map[string]TagStats)
+	p.tags.entries.Range(func(k, v any) bool {
+		e := v.(*tagEntry)
+		stats[k.(string)] = TagStats{
+			Succeeded: e.succeeded.Load(),
+			Failed:    e.failed.Load(),
+		}
+		return true
+	})
+	return stats
+}
+
+// WaitTag 阻塞直到所有带标签 tag 的已提交任务结束（成功、失败、被跳过或被取消），
+// 用于在共享的池上设置阶段屏障，例如等所有索引写入完成后再切换别名。
+// 没有这类未结束的任务时立即返回 nil；ctx 先结束时返回 ctx 的错误。
+// 等待期间新提交的同标签任务同样会被等待。WaitTag 不会关闭池。
+func (p *Pool) WaitTag(ctx context.Context, tag string) error {
+	v, ok := p.tags.entries.Load(tag)
+	if !ok {
+		return nil
+	}
+	e := v.(*tagEntry)
+	e.mu.Lock()
+	if e.pending == 0 {
+		e.mu.Unlock()
+		return nil
+	}
+	idle := e.idle
+	e.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,7 +1,190 @@
 package gopoolx
 
-import "context"
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
 
 // Task 是提交到 Pool 中执行的基本任务类型。
 // 参数为上层传入的上下文，允许任务根据 ctx 进行超时或取消控制。
 type Task func(ctx context.Context) error
+
+// Submitter 是可以提交任务的对象，*Pool 实现了该接口。
+// 业务代码依赖 Submitter 而不是 *Pool 时，测试中即可注入 pooltest.Recorder 等替身。
+type Submitter interface {
+	Submit(task Task, opts ...SubmitOption) error
+}
+
+// SubmitOption 是单次提交时的可选配置，只作用于当前提交的任务。
+type SubmitOption func(*submitOptions)
+
+// submitOptions 保存单次提交的配置项。
+type submitOptions struct {
+	// lane 是任务所属的通道（lane），用于按通道限流等场景
+	lane string
+	// overlap 是周期任务的重叠处理策略，仅对 SubmitEvery 生效
+	overlap OverlapPolicy
+	// name 是任务名，用于诊断
+	name string
+	// tags 是任务的标签，见 WithTags
+	tags []string
+	// fallback 是任务最终失败时的降级函数，见 WithFallback
+	fallback func(ctx context.Context, err error) error
+	// ack / nack 是任务最终成功与失败时的确认回调，见 WithAck
+	ack  func()
+	nack func(err error)
+	// idempotencyKey 是任务的幂等键，见 WithIdempotencyKey
+	idempotencyKey string
+	// softTimeout 是任务的软超时，见 WithSoftTimeout
+	softTimeout time.Duration
+	// key 是任务的键，见 WithKey
+	key string
+}
+
+// WithLane 指定任务所属的通道（lane）。
+// 同一个池可以通过不同 lane 复用于多个下游，并配合 WithLaneRateLimit 分别限流。
+func WithLane(lane string) SubmitOption {
+	return func(o *submitOptions) {
+		o.lane = lane
+	}
+}
+
+// WithTaskName 为任务指定名称。
+// 命名任务的最终错误会被包装为 *TaskError，Errors、Future 与任务快照（TaskInfo.Name）
+// 中都能据此定位是哪个任务出了问题，而不是只看到一个匿名闭包的报错。
+func WithTaskName(name string) SubmitOption {
+	return func(o *submitOptions) {
+		o.name = name
+	}
+}
+
+// runner 是队列中任务的执行体。Task 本身实现了 runner；
+// TypedPool 等场景可以用携带参数的结构体实现它，避免为每次提交额外分配闭包。
+type runner interface {
+	run(ctx context.Context) error
+}
+
+// run 实现 runner。
+func (t Task) run(ctx context.Context) error {
+	return t(ctx)
+}
+
+// job 是任务在队列中的内部表示，携带提交时确定的元数据。
+type job struct {
+	// task 是任务的执行体
+	task runner
+	// lane 是任务所属的通道，空字符串表示默认通道
+	lane string
+	// overlap 是周期任务的重叠处理策略，仅对 SubmitEvery 生效
+	overlap OverlapPolicy
+	// name 是任务名，空字符串表示匿名任务
+	name string
+	// tags 是任务的标签，为 nil 表示未打标签
+	tags []string
+	// fallback 是任务最终失败时的降级函数，为 nil 表示不降级
+	fallback func(ctx context.Context, err error) error
+	// ack / nack 在任务有了最终结果时调用，均为 nil 表示不需要确认
+	ack  func()
+	nack func(err error)
+	// idempotencyKey 是在每次执行的 ctx 中注入的幂等键，空字符串表示没有
+	idempotencyKey string
+	// softTimeout 是任务的软超时，0 表示没有，见 Checkpoint
+	softTimeout time.Duration
+	// key 是任务的键，空字符串表示没有
+	key string
+	// attempts 是重新入队等待重试的任务此前已执行的次数，retryErr 是最近一次的错误，
+	// retryAt 是下一次重试的最早时间，见 WithRetryRequeue
+	attempts int
+	retryErr error
+	retryAt  time.Time
+	// coalesce 是任务所在的合并窗口，未参与合并时为 nil，见 WithCoalesceWindow
+	coalesce *coalesceGroup
+	// ctx 是提交方的上下文（见 SubmitWithContext），为 nil 时只使用池的上下文
+	ctx context.Context
+	// lineage 是带返回值的任务供后续任务继承的属性，执行时记录其 ctx，见 Then
+	lineage *lineage
+	// enqueuedAt 是任务入队的时间，仅在启用 WithMaxQueueAge、WithLatencyHistogram 或 WithOnTaskComplete 时记录
+	enqueuedAt time.Time
+	// after 在任务执行结束（含全部重试）后由 worker 调用，参数为最终错误，可为 nil
+	after func(err error)
+	// handle 是任务的句柄，用于状态跟踪与取消；未请求句柄的任务为 nil
+	handle *TaskHandle
+	// pooled 表示任务取自对象池，执行结束后没有其他引用时会被回收复用
+	pooled bool
+}
+
+// recycler 由可复用的 runner 实现（如 TypedPool 的任务），回收时归还到各自的对象池。
+type recycler interface {
+	recycle()
+}
+
+// jobPool 复用 Submit 热路径上的 job，使稳定状态下的提交不产生分配。
+var jobPool = sync.Pool{
+	New: func() any { return new(job) },
+}
+
+// acquireJob 从对象池中取出一个 job 并按提交选项初始化，语义与 newJob 相同。
+// 只有执行结束后不再被引用的任务才能使用它，见 release。
+func acquireJob(task Task, opts []SubmitOption) *job {
+	j := jobPool.Get().(*job)
+	j.task = task
+	j.pooled = true
+	j.apply(opts)
+	return j
+}
+
+// release 回收一个已执行结束的对象池任务。调用方需保证任务不再被引用
+// （例如没有句柄），否则不得调用。
+func (j *job) release() {
+	if r, ok := j.task.(recycler); ok {
+		r.recycle()
+		return
+	}
+	*j = job{}
+	jobPool.Put(j)
+}
+
+// newJob 根据提交选项构建队列中的任务。
+func newJob(task Task, opts []SubmitOption) *job {
+	j := &job{task: task}
+	j.apply(opts)
+	return j
+}
+
+// apply 将单次提交的选项写入任务的元数据。
+func (j *job) apply(opts []SubmitOption) {
+	if len(opts) == 0 {
+		return
+	}
+	var so submitOptions
+	for _, opt := range opts {
+		opt(&so)
+	}
+	j.lane = so.lane
+	j.overlap = so.overlap
+	j.name = so.name
+	j.tags = so.tags
+	j.fallback = so.fallback
+	j.ack, j.nack = so.ack, so.nack
+	j.idempotencyKey = so.idempotencyKey
+	j.softTimeout = so.softTimeout
+	j.key = so.key
+}
+
+// wrapErr 为任务的最终错误附加所在池的名称 pool、任务名与执行次数 attempts，包装为 *TaskError。
+// 未命名池中未经重试的匿名任务错误原样返回；已由 NamedTask 以同名包装的错误不会重复包装。
+func (j *job) wrapErr(err error, attempts int, pool string) error {
+	if j.name == "" && attempts <= 1 && pool == "" {
+		return err
+	}
+	var te *TaskError
+	if errors.As(err, &te) && te.Name == j.name {
+		if attempts <= 1 && te.Pool == pool {
+			return err
+		}
+		err = te.Err
+	}
+	return &TaskError{Pool: pool, Name: j.name, Attempts: attempts, Err: err}
+}
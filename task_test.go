@@ -0,0 +1,37 @@
+package gopoolx
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+// noop 是不捕获任何变量的任务，提交它本身不会产生分配。
+func noop(context.Context) error { return nil }
+
+// warmJobs 先提交并执行完 n 个任务，使任务对象池中已有足够的可复用对象，
+// 之后的 n 次提交不必等待 worker 归还对象。
+func warmJobs(p *Pool, n int, submit func()) {
+	for i := 0; i < n; i++ {
+		submit()
+	}
+	for p.Pending() > 0 {
+		runtime.Gosched()
+	}
+	// 计数在对象归还之前释放，再让出一次使 worker 完成归还
+	runtime.Gosched()
+}
+
+func TestSubmitAllocs(t *testing.T) {
+	p := newRunningPool(t, 1, WithQueueSize(1024))
+	submit := func() { p.Submit(noop) }
+	warmJobs(p, 1000, submit)
+	allocs := testing.AllocsPerRun(1000, submit)
+	if allocs != 0 {
+		t.Fatalf("Submit allocates %v times per call, want 0", allocs)
+	}
+}
+
+func BenchmarkSubmit(b *testing.B) {
+	benchmarkPool(b, New(1, WithQueueSize(1024)))
+}
@@ -0,0 +1,139 @@
+package gopoolx
+
+import (
+	"context"
+	"slices"
+)
+
+// Then 在 f 成功完成后，将 fn(结果) 作为新任务调度到产生 f 的同一个池中执行，
+// 返回代表后续结果的 Future，从而无需手动启动 goroutine 即可串联异步流程。
+// 说明：
+//   - 若 f 失败，fn 不会执行，返回的 Future 以相同错误完成
+//   - fn 按池的配置重试，panic 会被捕获并转换为 error
+//   - f 不来自池（例如手动完成的 Future）时，fn 在完成 f 的 goroutine 中直接执行
+//   - 后续任务继承产生 f 的任务的通道（WithLane）、标签（WithTags）与 ctx 中的值（见下文），
+//     异步流程的每一步都按原任务的调度类别限流、统计，并归入同一条追踪
+//
+// 继承的 ctx 取自原任务最近一次执行时的 ctx：值（提交方 ctx 中的 trace ID 等、启用 WithTracer 时原任务的 span）
+// 照常可见，后续任务的 span 因此成为原任务 span 的子 span；但它不随原任务的 ctx 取消，也不带截止时间，
+// 池为单次执行注入的值（Attempt、IdempotencyKey、WithSoftTimeout 等）不会被继承。
+// 池没有任务优先级的概念，通道与标签就是任务的调度类别；键（WithKey）不会被继承。
+func Then[T, U any](f *Future[T], fn func(v T) (U, error)) *Future[U] {
+	return ThenApply(f, func(_ context.Context, v T) (U, error) {
+		return fn(v)
+	})
+}
+
+// ThenApply 与 Then 相同，但 fn 额外接收执行时的 ctx（池中执行时为 worker 的 ctx 与继承的值），
+// 便于后续任务响应取消。
+func ThenApply[T, U any](f *Future[T], fn func(ctx context.Context, v T) (U, error)) *Future[U] {
+	next := newFuture[U]()
+	next.pool = f.pool
+
+	f.onComplete(func() {
+		if f.err != nil {
+			var zero U
+			next.complete(zero, f.err)
+			return
+		}
+
+		v := f.result
+		call := func(ctx context.Context) (U, error) {
+			return fn(ctx, v)
+		}
+		if f.pool == nil {
+			res, err := safeCall(context.Background(), call)
+			next.complete(res, err)
+			return
+		}
+		submitFuncAsync(f.pool, call, f.lineage, next)
+	})
+	return next
+}
+
+// Transform 在 f 成功完成后以 fn(结果) 完成返回的 Future，用于廉价的纯转换（解析、字段提取等）。
+// 与 Then 不同，fn 不会被调度到池中，而是在完成 f 的 goroutine 中直接执行（f 已完成时在调用方中执行），
+// 不占用 worker、不重试，也不计入池的统计与 Errors，因此 fn 应快速返回且不阻塞。
+// 若 f 失败，fn 不会执行，返回的 Future 以相同错误完成；fn 中的 panic 会被转换为 error。
+func Transform[T, U any](f *Future[T], fn func(v T) (U, error)) *Future[U] {
+	next := newFuture[U]()
+	next.pool = f.pool
+	// 转换不产生新任务，之后的 Then 仍继承原任务的属性
+	next.lineage = f.lineage
+
+	f.onComplete(func() {
+		if f.err != nil {
+			var zero U
+			next.complete(zero, f.err)
+			return
+		}
+		v := f.result
+		next.complete(safeCall(context.Background(), func(context.Context) (U, error) {
+			return fn(v)
+		}))
+	})
+	return next
+}
+
+// safeCall 在当前 goroutine 中执行 fn，并将 panic 转换为 error。
+func safeCall[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (res T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			res, err = zero, panicError(r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// lineage 是 Future 的后续任务（见 Then）从产生它的任务继承的属性。
+type lineage struct {
+	lane string
+	tags []string
+	// ctx 是原任务最近一次执行时的 ctx（尚未执行时为提交方的 ctx），只用于取值，可为 nil
+	ctx context.Context
+}
+
+// newLineage 记录带返回值的任务 j 的可继承属性，并让 j 在执行时更新其 ctx。
+func newLineage(j *job) *lineage {
+	l := &lineage{lane: j.lane, tags: slices.Clone(j.tags), ctx: j.ctx}
+	j.lineage = l
+	return l
+}
+
+// options 返回使后续任务继承通道与标签的提交选项；l 为 nil 时返回 nil。
+func (l *lineage) options() []SubmitOption {
+	if l == nil {
+		return nil
+	}
+	var opts []SubmitOption
+	if l.lane != "" {
+		opts = append(opts, WithLane(l.lane))
+	}
+	if len(l.tags) > 0 {
+		opts = append(opts, WithTags(l.tags...))
+	}
+	return opts
+}
+
+// context 返回后续任务的提交方 ctx：只提供原任务 ctx 中的值，没有可继承的 ctx 时返回 nil。
+func (l *lineage) context() context.Context {
+	if l == nil || l.ctx == nil {
+		return nil
+	}
+	return inheritedContext{context.WithoutCancel(l.ctx)}
+}
+
+// inheritedContext 屏蔽池为单次执行注入的值，这些值属于原任务，不应被后续任务看到
+// （所属的池与 worker 的暂存对象会在执行时重新附加，见 Pool.run）。
+type inheritedContext struct {
+	context.Context
+}
+
+func (c inheritedContext) Value(key any) any {
+	switch key.(type) {
+	case attemptKey, idempotencyKey, softDeadlineKey, spanKey, poolKey, workerBufferKey:
+		return nil
+	}
+	return c.Context.Value(key)
+}
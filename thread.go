@@ -0,0 +1,45 @@
+package gopoolx
+
+import (
+	"context"
+	"runtime"
+)
+
+// WithLockOSThread 让每个 worker 在其生命周期内锁定到一个操作系统线程（runtime.LockOSThread），
+// 用于依赖线程局部状态的 cgo 库（如 OpenGL、部分机器学习运行时）：同一 worker 执行的任务
+// 总在同一线程上运行。worker 退出时不解除锁定，线程随 goroutine 一起销毁，
+// 其线程局部状态不会被其他 goroutine 复用。
+//
+// 任务只有在 worker goroutine 中执行时才在锁定的线程上：启用 WithHardTimeout 时每次执行
+// 都在独立的 goroutine 中进行，同步模式下任务在提交方 goroutine 中执行，二者都不受此选项约束。
+func WithLockOSThread() Option {
+	return func(o *Options) {
+		o.lockOSThread = true
+	}
+}
+
+// WithWorkerHooks 设置 worker 的启动与退出回调（均可为 nil），它们在 worker goroutine 中、
+// 执行第一个任务之前与退出之前调用，配合 WithLockOSThread 可用于初始化与释放线程局部资源。
+func WithWorkerHooks(onStart, onStop func()) Option {
+	return func(o *Options) {
+		o.onWorkerStart = onStart
+		o.onWorkerStop = onStop
+	}
+}
+
+// startWorker 完成 worker goroutine 的启动准备（线程锁定、启动回调、登记），返回对应的收尾函数。
+func (p *Pool) startWorker(ctx context.Context) func() {
+	if p.opts.lockOSThread {
+		runtime.LockOSThread()
+	}
+	if p.opts.onWorkerStart != nil {
+		p.opts.onWorkerStart()
+	}
+	unregister := p.registerWorker(ctx)
+	return func() {
+		unregister()
+		if p.opts.onWorkerStop != nil {
+			p.opts.onWorkerStop()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package gopoolx
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Tracer 是与具体追踪系统无关的 span 工厂，OpenTelemetry 等实现只需做一层薄适配即可接入。
+type Tracer interface {
+	// Start 为一次任务执行开启名为 name 的 span，返回携带该 span 的 ctx，任务以它执行
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span 是一次任务执行对应的追踪区间。
+type Span interface {
+	// AddEvent 在 span 上记录一个带属性的事件
+	AddEvent(name string, attrs ...Attr)
+	// End 以任务的最终错误（成功时为 nil）结束 span
+	End(err error)
+}
+
+// Attr 是 span 事件的一个属性。
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// 池在任务 span 上记录的事件名。
+const (
+	// SpanEventRetry 在每次失败后、下一次重试前记录，属性为 attempt（失败的第几次执行）、
+	// error（错误信息）与 backoff（重试前的等待时长）
+	SpanEventRetry = "retry"
+	// SpanEventPanic 在任务 panic 被恢复时记录，属性为 attempt、value（panic 的值）与 stack（调用栈）
+	SpanEventPanic = "panic"
+)
+
+// defaultSpanName 是未命名任务（见 WithTaskName）的 span 名。
+const defaultSpanName = "gopoolx.task"
+
+// WithTracer 为每个被执行的任务开启一个 span（任务名作为 span 名），覆盖从首次执行到重试、
+// 降级结束的全过程：每次重试与被恢复的 panic 都作为事件记录在 span 上，
+// 一条追踪即可看清一个不稳定任务的完整经过，而不只是它最终的耗时。
+func WithTracer(t Tracer) Option {
+	return func(o *Options) {
+		o.tracer = t
+	}
+}
+
+// spanKey 是 span 在 ctx 中的键。
+type spanKey struct{}
+
+// startSpan 为任务 j 开启 span，并将其同时保存在返回的 ctx 中供 retryLoop 记录事件。
+func (p *Pool) startSpan(ctx context.Context, j *job) (context.Context, Span) {
+	name := j.name
+	if name == "" {
+		name = defaultSpanName
+	}
+	ctx, span := p.opts.tracer.Start(ctx, name)
+	if sa, ok := span.(SpanAttributer); ok && p.spanAttrs != nil {
+		sa.SetAttributes(p.spanAttrs...)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// taskSpan 返回 ctx 中任务的 span，未启用 WithTracer 时返回 nil。
+func (p *Pool) taskSpan(ctx context.Context) Span {
+	if p.opts.tracer == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(Span)
+	return span
+}
+
+// traceRetry 在 span 上记录第 attempt 次执行因 err 失败、等待 backoff 后重试。
+func traceRetry(span Span, attempt int, err error, backoff time.Duration) {
+	span.AddEvent(SpanEventRetry,
+		Attr{Key: "attempt", Value: attempt},
+		Attr{Key: "error", Value: err.Error()},
+		Attr{Key: "backoff", Value: backoff},
+	)
+}
+
+// tracePanic 在 err 为被恢复的 panic 时将其记录到 span 上。
+func tracePanic(span Span, attempt int, err error) {
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		return
+	}
+	span.AddEvent(SpanEventPanic,
+		Attr{Key: "attempt", Value: attempt},
+		Attr{Key: "value", Value: pe.Value},
+		Attr{Key: "stack", Value: string(pe.Stack)},
+	)
+}
@@ -0,0 +1,82 @@
+package gopoolx
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// tunables 保存可在运行期间通过 Set* 方法修改的配置项。
+// 它们在 New 中由对应的 Option 初始化，之后只通过原子操作读写。
+type tunables struct {
+	retry           atomic.Int64
+	retryDelay      atomic.Int64
+	queueFullPolicy atomic.Int32
+	// limiter 为 nil 表示不限流
+	limiter atomic.Pointer[Limiter]
+}
+
+// init 从 opts 中读取初始值。
+func (t *tunables) init(o *Options) {
+	t.retry.Store(int64(o.retry))
+	t.retryDelay.Store(int64(o.retryDelay))
+	t.queueFullPolicy.Store(int32(o.queueFullPolicy))
+	if o.limiter != nil {
+		t.limiter.Store(&o.limiter)
+	}
+}
+
+// SetRetry 在运行期间修改任务失败时的重试次数，语义与 WithRetry 相同。
+// 新值从之后开始执行的任务起生效，正在重试的任务仍按开始执行时的次数。
+// 与其他 Set* 方法一样可以与提交、执行并发调用，便于通过管理接口调整运行中的池。
+func (p *Pool) SetRetry(n int) {
+	p.tune.retry.Store(int64(n))
+}
+
+// SetRetryDelay 在运行期间修改重试之间的间隔时间，语义与 WithRetryDelay 相同，
+// 对下一次重试立即生效。
+func (p *Pool) SetRetryDelay(d time.Duration) {
+	p.tune.retryDelay.Store(int64(d))
+}
+
+// SetQueueFullPolicy 在运行期间修改队列满时的处理策略，语义与 WithQueueFullPolicy 相同。
+// 新策略对之后的提交生效，已阻塞等待入队的提交不受影响。
+func (p *Pool) SetQueueFullPolicy(policy QueueFullPolicy) {
+	p.tune.queueFullPolicy.Store(int32(policy))
+}
+
+// SetRateLimit 在运行期间替换任务启动的限流器，语义与 WithRateLimit 相同，
+// 也会替换通过 WithLimiter 设置的自定义限流器。新的令牌桶初始为满桶；
+// 正在等待旧令牌桶的任务仍按旧速率放行。rps <= 0 表示取消限流。
+func (p *Pool) SetRateLimit(rps float64, burst int) {
+	if rps <= 0 {
+		p.tune.limiter.Store(nil)
+		return
+	}
+	tb := newTokenBucket(rps, burst)
+	tb.setClock(p.opts.clock)
+	var l Limiter = tb
+	p.tune.limiter.Store(&l)
+}
+
+// retryLimit 返回当前的重试次数。
+func (p *Pool) retryLimit() int {
+	return int(p.tune.retry.Load())
+}
+
+// retryDelay 返回当前的重试间隔。
+func (p *Pool) retryDelay() time.Duration {
+	return time.Duration(p.tune.retryDelay.Load())
+}
+
+// queueFullPolicy 返回当前的队列满策略。
+func (p *Pool) queueFullPolicy() QueueFullPolicy {
+	return QueueFullPolicy(p.tune.queueFullPolicy.Load())
+}
+
+// limiter 返回当前的全局限流器，未启用时返回 nil。
+func (p *Pool) limiter() Limiter {
+	if l := p.tune.limiter.Load(); l != nil {
+		return *l
+	}
+	return nil
+}
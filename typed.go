@@ -0,0 +1,71 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+)
+
+// TypedPool 是为"同一个处理函数、大量不同输入"场景设计的泛型池。
+// 所有任务共享构造时传入的 handler，Submit 只需要传入输入值，
+// 调用方无需为每个任务构造闭包；内部任务对象会被回收复用，稳定状态下提交不产生分配。
+//
+// TypedPool 内嵌 *Pool，Run、Wait、Errors 以及各项配置的语义与 Pool 完全相同；
+// 仍可通过 tp.Pool.Submit 提交普通 Task。
+type TypedPool[In any] struct {
+	*Pool
+	handler func(ctx context.Context, in In) error
+	// jobs 复用已执行结束的 typedJob
+	jobs sync.Pool
+}
+
+// typedJob 是 TypedPool 在队列中的任务，直接携带输入值而不是闭包。
+type typedJob[In any] struct {
+	job
+	handler func(ctx context.Context, in In) error
+	in      In
+	// owner 是任务回收时归还的对象池
+	owner *sync.Pool
+}
+
+// run 实现 runner。
+func (t *typedJob[In]) run(ctx context.Context) error {
+	return t.handler(ctx, t.in)
+}
+
+// recycle 实现 recycler：清空任务后归还到所属 TypedPool 的对象池。
+func (t *typedJob[In]) recycle() {
+	owner := t.owner
+	*t = typedJob[In]{owner: owner}
+	owner.Put(t)
+}
+
+// NewTyped 创建一个使用 handler 处理所有输入的泛型池。
+//   - workerNum: worker 的数量（应为正数）
+//   - handler: 处理单个输入的函数，返回的错误会触发重试并被收集
+//   - opts: 与 New 相同的可选配置
+func NewTyped[In any](workerNum int, handler func(ctx context.Context, in In) error, opts ...Option) *TypedPool[In] {
+	tp := &TypedPool[In]{
+		Pool:    New(workerNum, opts...),
+		handler: handler,
+	}
+	tp.jobs.New = func() any {
+		return &typedJob[In]{owner: &tp.jobs}
+	}
+	return tp
+}
+
+// Submit 提交一个输入值，由 handler 在 worker 中处理。
+// 队列满策略、返回值与 Pool.Submit 相同；opts 为单次提交的可选配置。
+func (tp *TypedPool[In]) Submit(in In, opts ...SubmitOption) error {
+	t := tp.jobs.Get().(*typedJob[In])
+	t.handler = tp.handler
+	t.in = in
+	t.job.task = t
+	t.job.pooled = true
+	t.job.apply(opts)
+
+	if err := tp.Pool.submit(&t.job); err != ErrDiscarded {
+		return err
+	}
+	return nil
+}
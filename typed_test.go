@@ -0,0 +1,30 @@
+package gopoolx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTypedPoolSubmitAllocs(t *testing.T) {
+	tp := NewTyped(1, func(context.Context, int) error { return nil }, WithQueueSize(1024))
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go tp.Run(ctx)
+	submit := func() { tp.Submit(1) }
+	warmJobs(tp.Pool, 1000, submit)
+	allocs := testing.AllocsPerRun(1000, submit)
+	if allocs != 0 {
+		t.Fatalf("TypedPool.Submit allocates %v times per call, want 0", allocs)
+	}
+}
+
+func BenchmarkTypedPoolSubmit(b *testing.B) {
+	tp := NewTyped(1, func(context.Context, int) error { return nil }, WithQueueSize(1024))
+	go tp.Run(context.Background())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tp.Submit(i)
+	}
+	tp.Wait()
+}
@@ -0,0 +1,108 @@
+package gopoolx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// completions 统计已结束的任务数，并唤醒等待其达到目标值的 WaitN。
+type completions struct {
+	// n 是已结束（释放了计数）的任务总数
+	n atomic.Uint64
+	// waiting 是正在等待的 WaitN 数，为 0 时任务结束无需加锁检查
+	waiting atomic.Int32
+	mu      sync.Mutex
+	waiters []*completionWaiter
+}
+
+// completionWaiter 是一个等待已结束任务数达到 target 的 WaitN。
+type completionWaiter struct {
+	target uint64
+	ch     chan struct{}
+}
+
+// add 记录一个任务结束，并唤醒已达到目标的等待方。
+func (c *completions) add() {
+	n := c.n.Add(1)
+	if c.waiting.Load() == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	kept := c.waiters[:0]
+	for _, w := range c.waiters {
+		if n >= w.target {
+			close(w.ch)
+			c.waiting.Add(-1)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	clear(c.waiters[len(kept):])
+	c.waiters = kept
+}
+
+// wait 登记一个等待 target 的等待方；已达到目标时返回 nil。
+func (c *completions) wait(target uint64) *completionWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 先登记再检查计数，登记之后结束的任务一定会看到它
+	c.waiting.Add(1)
+	if c.n.Load() >= target {
+		c.waiting.Add(-1)
+		return nil
+	}
+	w := &completionWaiter{target: target, ch: make(chan struct{})}
+	c.waiters = append(c.waiters, w)
+	return w
+}
+
+// cancel 撤销尚未被唤醒的等待方 w。
+func (c *completions) cancel(w *completionWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, x := range c.waiters {
+		if x == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.waiting.Add(-1)
+			return
+		}
+	}
+}
+
+// WaitN 阻塞直到自调用起又有 n 个任务结束，便于"分批推进"式的协调（提交 1000 个、等其中 900 个结束、
+// 再继续提交），而不必在外部自行计数。任务无论成功、失败、被跳过、被取消还是被丢弃，只要释放了计数就算结束；
+// 提交时即被拒绝的任务不计入。n <= 0 时立即返回 nil。
+//
+// ctx 先结束时返回 ctx.Err()；池在此之前已被 Wait 关闭（不会再有任务结束）时返回 ErrPoolClosed。
+func (p *Pool) WaitN(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	w := p.completions.wait(p.completions.n.Load() + uint64(n))
+	if w == nil {
+		return nil
+	}
+	select {
+	case <-w.ch:
+		return nil
+	case <-ctx.Done():
+		p.completions.cancel(w)
+		select {
+		case <-w.ch:
+			return nil
+		default:
+		}
+		return ctx.Err()
+	case <-p.closed:
+		p.completions.cancel(w)
+		// 关闭前结束的任务可能恰好达到目标
+		select {
+		case <-w.ch:
+			return nil
+		default:
+		}
+		return ErrPoolClosed
+	}
+}
@@ -0,0 +1,46 @@
+package gopoolx
+
+import "context"
+
+// Warmup 以 ctx 启动 worker（等同于调用 Run(ctx)，之后无需再调用 Run），并在每个 worker 上
+// 各执行一次健康探测 probe，例如验证 worker 初始化（见 WithWorkerHooks）建立的数据库连接是否可用。
+// 探测以 Gang 的方式提交：每个探测都会等到全部探测开始后才结束，因此它们必定分别运行在不同的 worker 上。
+// 所有探测成功后池被标记为就绪（见 Ready）并返回 nil；有探测失败时返回各探测错误的聚合，
+// 池仍在运行但保持未就绪，调用方可以重新调用 Warmup 再次探测（此时不会重复启动 worker）；
+// ctx 结束时返回 ctx 的错误。
+// 之后通过 SetWorkers 等方式新增的 worker 不会被探测。同步模式下 probe 只执行一次。
+func (p *Pool) Warmup(ctx context.Context, probe Task) error {
+	p.warmupOnce.Do(func() {
+		p.Run(ctx)
+	})
+	n := p.workers()
+	if p.opts.synchronous {
+		n = 1
+	}
+	probes := make([]Task, n)
+	for i := range probes {
+		probes[i] = probe
+	}
+	if _, err := p.Gang(probes...).Get(ctx); err != nil {
+		return err
+	}
+	p.ready.Store(true)
+	return nil
+}
+
+// Ready 报告池是否已通过 Warmup 的探测且仍可接收任务，适合直接用作 Kubernetes 就绪探针：
+// Warmup 成功前、Run 的 ctx 结束后以及池被 Wait / Shutdown 关闭后都返回 false。
+// 它只读取原子状态与通道，可以被频繁调用。
+func (p *Pool) Ready() bool {
+	if !p.ready.Load() {
+		return false
+	}
+	select {
+	case <-p.quit:
+		return false
+	case <-p.closed:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,66 @@
+package gopoolx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// WithQueueWatermarks 设置队列深度（见 QueueLen）的高低水位回调，均为边沿触发：
+// 深度从低于 high 升至 high 时调用一次 onHigh，此后深度降至 low 时调用一次 onLow，如此交替，
+// 便于驱动生产方限速与告警而无需轮询。回调（可为 nil）串行执行，
+// 在使深度越过水位的提交方或 worker 中同步调用，应避免阻塞。
+// 要求 0 <= low < high，否则不启用。
+func WithQueueWatermarks(high, low int, onHigh, onLow func()) Option {
+	return func(o *Options) {
+		if low < 0 || low >= high {
+			o.watermarks = nil
+			return
+		}
+		o.watermarks = &watermarks{high: int64(high), low: int64(low), onHigh: onHigh, onLow: onLow}
+	}
+}
+
+// watermarks 跟踪队列深度所处的水位区间，并在越过水位时触发回调。
+type watermarks struct {
+	high, low     int64
+	onHigh, onLow func()
+
+	// above 表示已触发 onHigh、尚未触发 onLow
+	above atomic.Bool
+	// mu 串行化状态切换与回调
+	mu sync.Mutex
+}
+
+// observe 以当前队列深度 n 检查是否越过水位。未越过时只有一次原子读取。
+func (w *watermarks) observe(queued *atomic.Int64, n int64) {
+	if above := w.above.Load(); above && n > w.low || !above && n < w.high {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// 加锁期间深度可能已经变化，以最新值为准
+	n = queued.Load()
+	switch above := w.above.Load(); {
+	case !above && n >= w.high:
+		w.above.Store(true)
+		if w.onHigh != nil {
+			w.onHigh()
+		}
+	case above && n <= w.low:
+		w.above.Store(false)
+		if w.onLow != nil {
+			w.onLow()
+		}
+	}
+}
+
+// addQueued 调整排队计数，并在启用 WithMetricsSink 时更新仪表、启用 WithQueueWatermarks 时检查水位。
+func (p *Pool) addQueued(delta int64) {
+	n := p.queued.Add(delta)
+	if m := p.opts.metrics; m != nil {
+		m.SetGauge(MetricQueueDepth, float64(n))
+	}
+	if w := p.opts.watermarks; w != nil {
+		w.observe(&p.queued, n)
+	}
+}
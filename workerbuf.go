@@ -0,0 +1,78 @@
+package gopoolx
+
+import "context"
+
+// workerBufferKey 是 worker 暂存对象在 ctx 中的键。
+type workerBufferKey struct{}
+
+// WithWorkerBuffer 为每个 worker 维护一个可复用的暂存对象（例如 1MB 的编码缓冲区），由 newFn 在 worker
+// 第一次需要时创建，任务通过 WorkerBuffer 取得。同一个 worker 上的任务依次执行，因此对象无需加锁，
+// 也不会像全局的 sync.Pool 那样在大量 worker 之间争用。
+//
+// 对象跨任务保留，任务应在使用前自行重置其内容（如 buf = buf[:0]）。它只能在任务自身的 goroutine 中使用，
+// 不能在任务返回后继续持有或交给其他 goroutine。newFn 为 nil 表示不启用。
+func WithWorkerBuffer(newFn func() any) Option {
+	return func(o *Options) {
+		o.workerBuffer = newFn
+	}
+}
+
+// WorkerBuffer 返回执行当前任务的 worker 的暂存对象，见 WithWorkerBuffer。
+// 任务不在 worker 上执行时（内联执行、ReentrantCallerRuns 的嵌套执行等）返回由 newFn 新建的对象；
+// 池未启用 WithWorkerBuffer、处于同步模式，或 ctx 不是池中任务的 ctx 时返回 nil。
+func WorkerBuffer(ctx context.Context) any {
+	if b, ok := ctx.Value(workerBufferKey{}).(*workerBuffer); ok {
+		return b.get()
+	}
+	if p, ok := ctx.Value(poolKey{}).(*Pool); ok && p.opts.workerBuffer != nil {
+		return p.opts.workerBuffer()
+	}
+	return nil
+}
+
+// workerBuffer 是一个 worker 的暂存对象，只由该 worker（或它借出的任务）访问。
+type workerBuffer struct {
+	newFn func() any
+	// v 是暂存对象，尚未创建时为 nil
+	v any
+}
+
+// get 返回暂存对象，必要时先创建。
+func (b *workerBuffer) get() any {
+	if b.v == nil {
+		b.v = b.newFn()
+	}
+	return b.v
+}
+
+// workerBufferOf 返回 ctx 中 worker 的暂存对象，没有时返回 nil。
+func workerBufferOf(ctx context.Context) *workerBuffer {
+	b, _ := ctx.Value(workerBufferKey{}).(*workerBuffer)
+	return b
+}
+
+// withWorkerBuffer 在启用 WithWorkerBuffer 时为即将启动的 worker 附加一个暂存对象。
+func (p *Pool) withWorkerBuffer(ctx context.Context) context.Context {
+	if p.opts.workerBuffer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, workerBufferKey{}, &workerBuffer{newFn: p.opts.workerBuffer})
+}
+
+// lend 将暂存对象借给在其他 goroutine 中执行的任务（见 WithHardTimeout），返回附加了借出对象的 ctx。
+// 任务按时返回后以 giveBack 取回，被放弃时以 forfeit 放弃，使 worker 之后的任务不会与仍在运行的
+// goroutine 共用同一个对象。
+func (b *workerBuffer) lend(ctx context.Context) (context.Context, *workerBuffer) {
+	lent := &workerBuffer{newFn: b.newFn, v: b.v}
+	return context.WithValue(ctx, workerBufferKey{}, lent), lent
+}
+
+// giveBack 取回借出的对象（任务期间可能刚被创建）。
+func (b *workerBuffer) giveBack(lent *workerBuffer) {
+	b.v = lent.v
+}
+
+// forfeit 放弃已借出的对象，worker 下次需要时重新创建。
+func (b *workerBuffer) forfeit() {
+	b.v = nil
+}